@@ -4,104 +4,154 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	cli "github.com/urfave/cli/v3"
 
-	config "github.com/adzpm/glone/internal/config"
+	common "github.com/adzpm/glone/internal/app/common"
 	git "github.com/adzpm/glone/internal/git"
-	gitlab "github.com/adzpm/glone/internal/gitlab"
 	logger "github.com/adzpm/glone/internal/logger"
-	netrc "github.com/adzpm/glone/internal/netrc"
+	report "github.com/adzpm/glone/internal/report"
+	state "github.com/adzpm/glone/internal/state"
 )
 
+// Run clones all projects that are not cloned yet
 func Run(ctx context.Context, cmd *cli.Command) error {
-	// Create logger instance
+	return run(ctx, cmd, false)
+}
+
+// Sync clones missing projects and fast-forwards existing clones
+func Sync(ctx context.Context, cmd *cli.Command) error {
+	return run(ctx, cmd, true)
+}
+
+func run(ctx context.Context, cmd *cli.Command, update bool) error {
+	// Create logger instance, a dry run keeps stdout for the plan
+	dryRun := cmd.Bool("dry-run")
 	lgr := logger.New()
-	cfg := &config.Config{
-		GitLabHost:  cmd.String("gitlab-host"),
-		GitLabUser:  cmd.String("gitlab-user"),
-		GitLabToken: cmd.String("gitlab-token"),
-		Group:       cmd.String("group"),
-		TargetDir:   cmd.Args().First(),
-	}
-
-	// If TargetDir is not specified, use current directory
-	if cfg.TargetDir == "" {
-		wd, err := os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
+	if dryRun {
+		lgr = logger.New(logger.WithOutput(os.Stderr))
+
+		switch format := cmd.String("dry-run-format"); format {
+		case planFormatText, planFormatJSON:
+		default:
+			return fmt.Errorf("unsupported plan format: %s", format)
 		}
-		cfg.TargetDir = wd
 	}
 
-	// Load credentials from .netrc if not specified via flags
-	netrcLoader, err := netrc.NewLoader(netrc.WithLogger(lgr))
-	if err != nil {
-		return fmt.Errorf("error creating netrc loader: %w", err)
+	// Check the report format before the run, which may take long
+	reportFormat := cmd.String("report-format")
+	if path := cmd.String("report"); path != "" && reportFormat == "" {
+		reportFormat = report.FormatFromPath(path)
 	}
-	netrcCfg, err := netrcLoader.LoadCredentials()
-	if err != nil {
-		return fmt.Errorf("error loading .netrc: %w", err)
+	if reportFormat != "" {
+		if err := report.ValidateFormat(reportFormat); err != nil {
+			return err
+		}
 	}
 
-	if netrcCfg != nil {
-		cfg.Merge(netrcCfg)
-		lgr.Info("Using credentials from .netrc")
+	cfg, err := common.LoadConfig(ctx, cmd, lgr)
+	if err != nil {
+		return err
 	}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("configuration error: %w (must specify --gitlab-host, --gitlab-user and --gitlab-token or configure .netrc)", err)
+	// Create cloner
+	cloner, err := git.NewCloner(
+		git.WithLogger(lgr),
+		git.WithProtocol(cfg.Protocol),
+		git.WithSSHKey(cfg.SSHKey, cfg.SSHKeyPassphrase),
+		git.WithMirror(cfg.Mirror),
+		git.WithDepth(cfg.Depth),
+		git.WithSingleBranch(cfg.SingleBranch),
+		git.WithBranch(cfg.Branch),
+		git.WithFilter(cfg.CloneFilter),
+		git.WithUnshallow(update && cfg.Unshallow),
+		git.WithRetryPolicy(cfg.RetryPolicy()),
+		git.WithOnConflict(cfg.OnConflict),
+		git.WithLFS(cfg.LFS || cfg.LFSFetchOnly, cfg.LFSFetchOnly),
+		git.WithRecurseSubmodules(cfg.RecurseSubmodules),
+	)
+	if err != nil {
+		return fmt.Errorf("error creating cloner: %w", err)
 	}
 
-	// Create GitLab client
-	client, err := gitlab.NewClient(cfg, gitlab.WithLogger(lgr))
+	projects, err := common.ListProjects(ctx, cfg, lgr)
 	if err != nil {
 		return err
 	}
 
-	// Get project list
-	lgr.Info("Getting project list...")
-	projects, err := client.GetAllProjects(cfg.Group)
+	gitProjects := common.GitProjects(projects)
+	if cfg.Wikis {
+		wikis := common.WikiProjects(projects)
+		lgr.Infof("Found wikis: %d", len(wikis))
+		gitProjects = append(gitProjects, wikis...)
+	}
+
+	// Find projects that were renamed or transferred since the last run
+	st, err := state.Load(cfg.TargetDir)
 	if err != nil {
 		return err
 	}
+	moves := findMoves(st, cloner, gitProjects, cfg.GitLabHost, cfg.TargetDir)
+
+	// Sync fetches only projects with new activity unless --full is set
+	unchanged := map[*git.Project]bool{}
+	if update && !cmd.Bool("full") {
+		unchanged = findUnchanged(st, cfg, cloner, gitProjects)
+	}
+
+	if dryRun {
+		p := newPlan(cmd.Name, cloner, gitProjects, moves, unchanged, cfg.TargetDir, update)
+		if err := p.write(cmd.Root().Writer, cmd.String("dry-run-format")); err != nil {
+			return err
+		}
+		p.log(lgr)
 
-	lgr.Infof("Found projects: %d", len(projects))
+		return nil
+	}
 
 	// Create target directory if it doesn't exist
 	if err := os.MkdirAll(cfg.TargetDir, 0755); err != nil {
 		return fmt.Errorf("failed to create target directory: %w", err)
 	}
 
-	// Clone each project
-	successCount := 0
-	skipCount := 0
-	errorCount := 0
+	moveProjects(ctx, lgr, cloner, st, gitProjects, moves, cfg.GitLabHost, cfg.TargetDir)
 
-	// Create cloner
-	cloner := git.NewCloner(git.WithLogger(lgr))
-
-	for _, glProject := range projects {
-		// Convert gitlab.Project to git.Project to avoid dependency on gitlab package in git module
-		project := &git.Project{
-			Name:              glProject.Name,
-			PathWithNamespace: glProject.PathWithNamespace,
-			HTTPURLToRepo:     glProject.HTTPURLToRepo,
-		}
+	process := cloner.CloneProject
+	if update {
+		process = cloner.SyncProject
+	}
+
+	// Process projects in parallel
+	startedAt := time.Now()
+	fn := skipUnchanged(func(ctx context.Context, project *git.Project) (git.Result, error) {
+		return process(ctx, project, cfg.TargetDir, cfg.GitLabToken)
+	}, unchanged)
+	sum := runAll(ctx, lgr, cloner, gitProjects, cfg.TargetDir, cfg.Jobs, fn)
 
-		skipped, err := cloner.CloneProject(project, cfg.TargetDir, cfg.GitLabToken)
-		if err != nil {
-			lgr.Errorf("Error cloning %s: %v", project.Name, err)
-			errorCount++
-		} else if skipped {
-			skipCount++
-		} else {
-			successCount++
+	sum.log(lgr, update)
+
+	recordProjects(st, cfg, cloner, gitProjects, sum.entries)
+	if err := st.Save(); err != nil {
+		return err
+	}
+
+	// Write machine-readable report
+	if path := cmd.String("report"); path != "" {
+		rep := report.New(cmd.Name, startedAt, sum.entries)
+		if err := rep.WriteFile(path, reportFormat); err != nil {
+			return err
 		}
+		lgr.Infof("Report written to %s", path)
 	}
 
-	lgr.Infof("Completed. Success: %d, Skipped: %d, Errors: %d", successCount, skipCount, errorCount)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("interrupted: %w", err)
+	}
+
+	if sum.errors > 0 && !cmd.Bool("ignore-errors") {
+		return fmt.Errorf("%d projects failed", sum.errors)
+	}
 
 	return nil
 }
@@ -2,14 +2,102 @@ package clone
 
 import (
 	cli "github.com/urfave/cli/v3"
+
+	common "github.com/adzpm/glone/internal/app/common"
 )
 
 // Flags returns flags for the clone command
 func Flags() []cli.Flag {
-	return []cli.Flag{
+	return append(common.ProjectFlags(), []cli.Flag{
+		&cli.IntFlag{
+			Name:    "jobs",
+			Aliases: []string{"j"},
+			Usage:   "number of repositories to clone in parallel",
+			Value:   1,
+		},
+		&cli.StringFlag{
+			Name:  "report",
+			Usage: "write a report of the run to file (JSON, or JUnit XML for .xml files)",
+		},
+		&cli.StringFlag{
+			Name:  "report-format",
+			Usage: "report format: json or junit (default: by file extension)",
+		},
+		&cli.BoolFlag{
+			Name:  "ignore-errors",
+			Usage: "exit with zero status even if some projects failed",
+		},
+		&cli.StringFlag{
+			Name:  "on-conflict",
+			Usage: "what to do with project paths that are not git repositories: skip, backup, remove or fail",
+			Value: "skip",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print which projects would be cloned, updated, skipped or removed without changing anything",
+		},
+		&cli.StringFlag{
+			Name:  "dry-run-format",
+			Usage: "dry-run output format: text or json",
+			Value: "text",
+		},
+		&cli.BoolFlag{
+			Name:  "mirror",
+			Usage: "make bare mirror clones with all refs at <path>.git for backups",
+		},
+		&cli.BoolFlag{
+			Name:  "wikis",
+			Usage: "also clone project wikis to <path>.wiki",
+		},
+		&cli.BoolFlag{
+			Name:  "lfs",
+			Usage: "download Git LFS objects and replace pointer files with their contents",
+		},
+		&cli.BoolFlag{
+			Name:  "lfs-fetch-only",
+			Usage: "download Git LFS objects to .git/lfs without replacing pointer files",
+		},
+		&cli.BoolFlag{
+			Name:  "recurse-submodules",
+			Usage: "initialize and update submodules, reusing clones of sibling projects",
+		},
+		&cli.IntFlag{
+			Name:  "depth",
+			Usage: "create shallow clones with history truncated to n commits",
+		},
+		&cli.BoolFlag{
+			Name:  "single-branch",
+			Usage: "clone only the history of the checked-out branch",
+		},
+		&cli.StringFlag{
+			Name:  "branch",
+			Usage: "branch to check out, or \"default\" for the project's default branch",
+		},
+		&cli.StringFlag{
+			Name:  "filter",
+			Usage: "partial clone filter, e.g. blob:none (requires the git binary)",
+		},
+		&cli.BoolFlag{
+			Name:  "unshallow",
+			Usage: "sync: fetch the full history of shallow clones",
+		},
+		&cli.BoolFlag{
+			Name:  "full",
+			Usage: "sync: fetch all projects, also those without activity since the last sync",
+		},
+		&cli.StringFlag{
+			Name:  "protocol",
+			Usage: "clone protocol: https or ssh",
+			Value: "https",
+		},
+		&cli.StringFlag{
+			Name:  "ssh-key",
+			Usage: "private key file for ssh protocol (uses ssh-agent if not set)",
+		},
 		&cli.StringFlag{
-			Name:  "group",
-			Usage: "clone repositories only from specified group",
+			Name:    "ssh-key-passphrase",
+			Usage:   "passphrase for the ssh private key",
+			Sources: cli.EnvVars("GLONE_SSH_KEY_PASSPHRASE"),
 		},
-	}
+	}...)
 }
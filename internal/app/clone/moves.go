@@ -0,0 +1,71 @@
+package clone
+
+import (
+	"context"
+	"os"
+
+	git "github.com/adzpm/glone/internal/git"
+	logger "github.com/adzpm/glone/internal/logger"
+	state "github.com/adzpm/glone/internal/state"
+)
+
+// findMoves returns the recorded path with namespace of projects of host that
+// were renamed or transferred since the last run. Only clones that still exist
+// at the recorded path and whose current path is free are returned. Wikis
+// share the ID of their project and move together with it.
+func findMoves(st *state.State, cloner *git.Cloner, projects []*git.Project, host string, targetDir string) map[*git.Project]string {
+	moves := make(map[*git.Project]string)
+	for _, project := range projects {
+		recorded, ok := st.Project(host, project.ID)
+		if !ok || recorded.Path == "" {
+			continue
+		}
+
+		recordedPath := recorded.Path
+		if project.Wiki {
+			recordedPath += ".wiki"
+		}
+		if recordedPath == project.PathWithNamespace {
+			continue
+		}
+
+		oldPath := cloner.ProjectPath(targetDir, &git.Project{PathWithNamespace: recordedPath})
+		if !git.IsRepository(oldPath) {
+			continue
+		}
+
+		if _, err := os.Lstat(cloner.ProjectPath(targetDir, project)); err == nil {
+			continue
+		}
+
+		moves[project] = recordedPath
+	}
+
+	return moves
+}
+
+// moveProjects moves the clones of renamed or transferred projects to their
+// current paths. Projects that fail to move are cloned again at the new path.
+func moveProjects(ctx context.Context, lgr logger.Logger, cloner *git.Cloner, st *state.State, projects []*git.Project, moves map[*git.Project]string, host string, targetDir string) {
+	for _, project := range projects {
+		oldPath, ok := moves[project]
+		if !ok || ctx.Err() != nil {
+			continue
+		}
+
+		lgr.Infof("Project %s was moved from %s", project.PathWithNamespace, oldPath)
+		if err := cloner.MoveProject(project, oldPath, targetDir); err != nil {
+			lgr.Errorf("Error moving %s: %v", oldPath, err)
+			continue
+		}
+
+		// The state records the path of the project, not of its wiki
+		if project.Wiki {
+			continue
+		}
+
+		st.Update(host, project.ID, func(p *state.Project) {
+			p.Path = project.PathWithNamespace
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package clone
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	git "github.com/adzpm/glone/internal/git"
+	logger "github.com/adzpm/glone/internal/logger"
+)
+
+// Plan output formats
+const (
+	planFormatText = "text"
+	planFormatJSON = "json"
+)
+
+// plan is the dry-run output of clone and sync
+type plan struct {
+	Command string         `json:"command"`
+	Counts  map[string]int `json:"counts"`
+	Steps   []git.Step     `json:"projects"`
+}
+
+func newPlan(command string, cloner *git.Cloner, projects []*git.Project, moves map[*git.Project]string, unchanged map[*git.Project]bool, targetDir string, update bool) *plan {
+	p := &plan{
+		Command: command,
+		Counts:  make(map[string]int),
+		Steps:   make([]git.Step, 0, len(projects)),
+	}
+
+	for _, project := range projects {
+		step := cloner.Plan(project, targetDir, update)
+		if oldPath, ok := moves[project]; ok {
+			step.Action = git.ActionMove
+			step.Reason = "moved from " + oldPath
+		} else if unchanged[project] && step.Action == git.ActionUpdate {
+			step.Action = git.ActionSkip
+			step.Reason = "no activity since last sync"
+		}
+
+		p.Steps = append(p.Steps, step)
+		p.Counts[string(step.Action)]++
+	}
+
+	return p
+}
+
+// write prints the plan to w in the given format
+func (p *plan) write(w io.Writer, format string) error {
+	switch format {
+	case planFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p)
+	case planFormatText:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, step := range p.Steps {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", step.Action, step.Project, step.Reason)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unsupported plan format: %s", format)
+	}
+}
+
+// log writes the plan summary line
+func (p *plan) log(lgr logger.Logger) {
+	lgr.Infof("Dry run. Clone: %d, Update: %d, Move: %d, Skip: %d, Backup: %d, Remove: %d, Fail: %d",
+		p.Counts[string(git.ActionClone)], p.Counts[string(git.ActionUpdate)], p.Counts[string(git.ActionMove)],
+		p.Counts[string(git.ActionSkip)], p.Counts[string(git.ActionBackup)], p.Counts[string(git.ActionRemove)], p.Counts[string(git.ActionFail)])
+}
@@ -0,0 +1,140 @@
+package clone
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	git "github.com/adzpm/glone/internal/git"
+	logger "github.com/adzpm/glone/internal/logger"
+	report "github.com/adzpm/glone/internal/report"
+)
+
+// projectFunc processes a single project, e.g. clones or syncs it
+type projectFunc func(ctx context.Context, project *git.Project) (git.Result, error)
+
+// summary holds per-result counters and report entries shared between workers
+type summary struct {
+	mu      sync.Mutex
+	counts  map[git.Result]int
+	errors  int
+	flagged map[git.Result][]string
+	entries []report.Entry
+}
+
+func newSummary() *summary {
+	return &summary{
+		counts:  make(map[git.Result]int),
+		flagged: make(map[git.Result][]string),
+	}
+}
+
+func (s *summary) add(project *git.Project, result git.Result, err error, entry report.Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entry)
+
+	if err != nil {
+		s.errors++
+		return
+	}
+
+	s.counts[result]++
+
+	// Projects that need manual attention are reported by path
+	if slices.Contains(flaggedResults, result) {
+		s.flagged[result] = append(s.flagged[result], project.PathWithNamespace)
+	}
+}
+
+// flaggedResults are the results reported by path at the end of a run
+var flaggedResults = []git.Result{git.ResultDirty, git.ResultDiverged, git.ResultNoUpstream, git.ResultConflict, git.ResultBackedUp, git.ResultReplaced}
+
+var flaggedMessages = map[git.Result]string{
+	git.ResultDirty:      "Not updated",
+	git.ResultDiverged:   "Not updated",
+	git.ResultNoUpstream: "Not updated, branch has no upstream",
+	git.ResultConflict:   "Not cloned, path is not a git repository",
+	git.ResultBackedUp:   "Backed up to " + git.BackupDir + " and cloned",
+	git.ResultReplaced:   "Removed and cloned",
+}
+
+// log writes the summary line and the projects that were left untouched
+func (s *summary) log(lgr logger.Logger, update bool) {
+	conflicts := s.counts[git.ResultConflict] + s.counts[git.ResultBackedUp] + s.counts[git.ResultReplaced]
+
+	if !update {
+		lgr.Infof("Completed. Success: %d, Empty: %d, Skipped: %d, Conflicts: %d, Errors: %d",
+			s.counts[git.ResultCloned], s.counts[git.ResultEmpty], s.counts[git.ResultSkipped], conflicts, s.errors)
+	} else {
+		lgr.Infof("Completed. Cloned: %d, Updated: %d, Up-to-date: %d, Unchanged: %d, Empty: %d, Skipped: %d, Dirty: %d, Diverged: %d, No upstream: %d, Conflicts: %d, Errors: %d",
+			s.counts[git.ResultCloned], s.counts[git.ResultUpdated], s.counts[git.ResultUpToDate], s.counts[git.ResultUnchanged], s.counts[git.ResultEmpty], s.counts[git.ResultSkipped],
+			s.counts[git.ResultDirty], s.counts[git.ResultDiverged], s.counts[git.ResultNoUpstream], conflicts, s.errors)
+	}
+
+	for _, result := range flaggedResults {
+		paths := s.flagged[result]
+		if len(paths) == 0 {
+			continue
+		}
+
+		sort.Strings(paths)
+		lgr.Warnf("%s (%s): %s", flaggedMessages[result], result, strings.Join(paths, ", "))
+	}
+}
+
+// runAll processes projects using a pool of jobs workers.
+// No new projects are started once ctx is cancelled.
+func runAll(ctx context.Context, lgr logger.Logger, cloner *git.Cloner, projects []*git.Project, targetDir string, jobs int, fn projectFunc) *summary {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	sum := newSummary()
+	queue := make(chan *git.Project)
+
+	var wg sync.WaitGroup
+	for range jobs {
+		wg.Go(func() {
+			for project := range queue {
+				start := time.Now()
+				result, err := fn(ctx, project)
+
+				entry := report.Entry{
+					Project:  project.PathWithNamespace,
+					Outcome:  string(result),
+					Duration: time.Since(start),
+				}
+
+				if err != nil {
+					lgr.Errorf("Error processing %s: %v", project.Name, err)
+					entry.Outcome = report.OutcomeFailed
+					entry.Error = err.Error()
+				} else {
+					entry.Commit = git.HeadCommit(cloner.ProjectPath(targetDir, project))
+				}
+
+				sum.add(project, result, err, entry)
+			}
+		})
+	}
+
+feed:
+	for _, project := range projects {
+		select {
+		case <-ctx.Done():
+			lgr.Warn("Interrupted, waiting for running jobs to finish...")
+			break feed
+		case queue <- project:
+		}
+	}
+
+	close(queue)
+	wg.Wait()
+
+	return sum
+}
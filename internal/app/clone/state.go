@@ -0,0 +1,179 @@
+package clone
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	config "github.com/adzpm/glone/internal/config"
+	git "github.com/adzpm/glone/internal/git"
+	report "github.com/adzpm/glone/internal/report"
+	state "github.com/adzpm/glone/internal/state"
+)
+
+// currentResults are the results after which a clone matches GitLab
+var currentResults = map[string]bool{
+	string(git.ResultCloned):   true,
+	string(git.ResultUpdated):  true,
+	string(git.ResultUpToDate): true,
+	string(git.ResultBackedUp): true,
+	string(git.ResultReplaced): true,
+}
+
+// Clone options that add content to a clone. Projects are only skipped as
+// unchanged if their clone was brought up to date with all requested options.
+const (
+	optionLFS          = "lfs"
+	optionLFSFetchOnly = "lfs-fetch-only"
+	optionSubmodules   = "submodules"
+)
+
+// cloneOptions returns the clone options of cfg that add content to a clone
+func cloneOptions(cfg *config.Config) []string {
+	var options []string
+
+	switch {
+	case cfg.LFSFetchOnly:
+		options = append(options, optionLFSFetchOnly)
+	case cfg.LFS:
+		options = append(options, optionLFS)
+	}
+
+	if cfg.RecurseSubmodules {
+		options = append(options, optionSubmodules)
+	}
+
+	return options
+}
+
+// hasOptions reports whether a clone brought up to date with the recorded
+// options has the content of all requested options
+func hasOptions(recorded []string, requested []string) bool {
+	for _, option := range requested {
+		// Replaced pointer files imply downloaded objects
+		if option == optionLFSFetchOnly && slices.Contains(recorded, optionLFS) {
+			continue
+		}
+
+		if !slices.Contains(recorded, option) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// recordProjects records the outcome of the run and the local state of every
+// project that has a local clone. The last activity is only updated for
+// projects whose clone is known to match GitLab, so that projects that failed
+// or were not updated are picked up again by the next run.
+func recordProjects(st *state.State, cfg *config.Config, cloner *git.Cloner, projects []*git.Project, entries []report.Entry) {
+	byPath := make(map[string]report.Entry, len(entries))
+	for _, entry := range entries {
+		byPath[entry.Project] = entry
+	}
+
+	now := time.Now().UTC()
+	for _, project := range projects {
+		// Wikis share the ID of their project and are not tracked
+		if project.Wiki {
+			continue
+		}
+
+		projectPath := cloner.ProjectPath(cfg.TargetDir, project)
+		local := git.IsRepository(projectPath)
+		entry, processed := byPath[project.PathWithNamespace]
+
+		if !processed && !local {
+			continue
+		}
+
+		st.Update(cfg.GitLabHost, project.ID, func(p *state.Project) {
+			if local {
+				p.Path = project.PathWithNamespace
+				p.Protocol = cfg.Protocol
+			}
+
+			if !processed {
+				return
+			}
+
+			p.Outcome = entry.Outcome
+			p.UpdatedAt = &now
+
+			if entry.Outcome != report.OutcomeFailed && local {
+				p.Branches = git.BranchHeads(projectPath)
+			}
+
+			if currentResults[entry.Outcome] {
+				p.LastActivityAt = project.LastActivityAt
+				p.SyncedAt = &now
+				p.Options = cloneOptions(cfg)
+			}
+		})
+	}
+}
+
+// activityGranularity is how long GitLab may delay updating last_activity_at
+// after a push, as it updates the field at most once per hour
+const activityGranularity = time.Hour
+
+// findUnchanged returns the projects that had no activity since their clone
+// was last brought up to date with the clone options of cfg, according to the
+// state. Whether the clone has local changes doesn't matter, as syncing would
+// skip it anyway. Nothing is skipped when shallow clones are to be unshallowed.
+func findUnchanged(st *state.State, cfg *config.Config, cloner *git.Cloner, projects []*git.Project) map[*git.Project]bool {
+	unchanged := make(map[*git.Project]bool)
+	if cfg.Unshallow {
+		return unchanged
+	}
+
+	options := cloneOptions(cfg)
+	for _, project := range projects {
+		if project.Wiki {
+			continue
+		}
+
+		recorded, ok := st.Project(cfg.GitLabHost, project.ID)
+		if !ok || recorded.Path != project.PathWithNamespace {
+			continue
+		}
+
+		if project.LastActivityAt == nil || recorded.LastActivityAt == nil || recorded.SyncedAt == nil {
+			continue
+		}
+
+		if !project.LastActivityAt.Equal(*recorded.LastActivityAt) {
+			continue
+		}
+
+		// Activity shortly before the last sync may hide later pushes
+		if recorded.SyncedAt.Sub(*recorded.LastActivityAt) < activityGranularity {
+			continue
+		}
+
+		// Options turned on since the last sync must be applied to the clone
+		if !hasOptions(recorded.Options, options) {
+			continue
+		}
+
+		if !git.IsRepository(cloner.ProjectPath(cfg.TargetDir, project)) {
+			continue
+		}
+
+		unchanged[project] = true
+	}
+
+	return unchanged
+}
+
+// skipUnchanged wraps fn to skip projects without new activity
+func skipUnchanged(fn projectFunc, unchanged map[*git.Project]bool) projectFunc {
+	return func(ctx context.Context, project *git.Project) (git.Result, error) {
+		if unchanged[project] {
+			return git.ResultUnchanged, nil
+		}
+
+		return fn(ctx, project)
+	}
+}
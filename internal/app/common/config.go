@@ -0,0 +1,238 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	cli "github.com/urfave/cli/v3"
+
+	config "github.com/adzpm/glone/internal/config"
+	credentials "github.com/adzpm/glone/internal/credentials"
+	logger "github.com/adzpm/glone/internal/logger"
+)
+
+// LoadConfig builds the configuration from all sources. Flags and environment
+// variables take precedence over the config file profile, which takes
+// precedence over the credential sources (e.g. .netrc) and the defaults.
+func LoadConfig(ctx context.Context, cmd *cli.Command, lgr logger.Logger) (*config.Config, error) {
+	cfg, err := flagConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the selected profile from the config file
+	fileCfg, err := loadConfigFile(cmd)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Merge(fileCfg)
+
+	// Load missing credentials from the credential sources
+	if err := loadCredentials(ctx, cmd, cfg, lgr); err != nil {
+		return nil, err
+	}
+
+	cfg.Merge(config.Defaults())
+
+	// If TargetDir is not specified, use current directory
+	if cfg.TargetDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		cfg.TargetDir = wd
+	}
+
+	if err := cfg.Filter.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("configuration error: %w (must specify --gitlab-host, --gitlab-user and --gitlab-token or configure a credential source)", err)
+	}
+
+	return cfg, nil
+}
+
+// explicitFlags maps flags whose zero value is meaningful to their settings
+var explicitFlags = map[string]config.Field{
+	"group":              config.FieldSelection,
+	"user":               config.FieldSelection,
+	"owned":              config.FieldSelection,
+	"starred":            config.FieldSelection,
+	"personal":           config.FieldSelection,
+	"mirror":             config.FieldMirror,
+	"wikis":              config.FieldWikis,
+	"lfs":                config.FieldLFS,
+	"lfs-fetch-only":     config.FieldLFSFetchOnly,
+	"recurse-submodules": config.FieldRecurseSubmodules,
+	"single-branch":      config.FieldSingleBranch,
+	"depth":              config.FieldDepth,
+}
+
+// flagConfig returns the configuration set by flags and environment variables.
+// Flags that were not set are left empty, so that their defaults don't
+// override values from the config file.
+func flagConfig(cmd *cli.Command) (*config.Config, error) {
+	cfg := &config.Config{
+		GitLabHost:  cmd.String("gitlab-host"),
+		GitLabUser:  cmd.String("gitlab-user"),
+		GitLabToken: cmd.String("gitlab-token"),
+
+		TokenCommand: cmd.String("token-command"),
+		TokenFile:    cmd.String("token-file"),
+
+		Selection: config.Selection{
+			Groups:   cmd.StringSlice("group"),
+			Users:    cmd.StringSlice("user"),
+			Owned:    cmd.Bool("owned"),
+			Starred:  cmd.Bool("starred"),
+			Personal: cmd.Bool("personal"),
+		},
+		TargetDir: cmd.Args().First(),
+
+		SSHKey:           cmd.String("ssh-key"),
+		SSHKeyPassphrase: cmd.String("ssh-key-passphrase"),
+		Mirror:           cmd.Bool("mirror"),
+		Wikis:            cmd.Bool("wikis"),
+
+		LFS:          cmd.Bool("lfs"),
+		LFSFetchOnly: cmd.Bool("lfs-fetch-only"),
+
+		RecurseSubmodules: cmd.Bool("recurse-submodules"),
+
+		Depth:        cmd.Int("depth"),
+		SingleBranch: cmd.Bool("single-branch"),
+		Branch:       cmd.String("branch"),
+		CloneFilter:  cmd.String("filter"),
+		Unshallow:    cmd.Bool("unshallow"),
+
+		Filter: config.Filter{
+			Visibility: cmd.String("visibility"),
+			Topics:     cmd.StringSlice("topic"),
+			Include:    cmd.StringSlice("include"),
+			Exclude:    cmd.StringSlice("exclude"),
+
+			ExcludeGroups: cmd.StringSlice("exclude-group"),
+		},
+	}
+
+	if cmd.IsSet("credential-sources") {
+		sources, err := credentials.ParseSources(cmd.String("credential-sources"))
+		if err != nil {
+			return nil, err
+		}
+		cfg.CredentialSources = sources
+	}
+
+	if cmd.IsSet("jobs") {
+		cfg.Jobs = cmd.Int("jobs")
+	}
+
+	if cmd.IsSet("retries") {
+		cfg.Retries = max(cmd.Int("retries"), 0)
+		cfg.Set |= config.FieldRetries
+	}
+
+	// Mark settings that may be set to their zero value, e.g. --mirror=false
+	// overrides mirror: true in the config file
+	for name, field := range explicitFlags {
+		if cmd.IsSet(name) {
+			cfg.Set |= field
+		}
+	}
+
+	if cmd.IsSet("retry-backoff") {
+		cfg.RetryBackoff = cmd.Duration("retry-backoff")
+	}
+
+	if cmd.IsSet("on-conflict") {
+		cfg.OnConflict = cmd.String("on-conflict")
+	}
+
+	if cmd.IsSet("protocol") {
+		cfg.Protocol = cmd.String("protocol")
+	}
+
+	if cmd.IsSet("archived") {
+		cfg.Filter.Archived = cmd.String("archived")
+	}
+
+	// Parse project filters
+	activeSince, err := config.ParseActiveSince(cmd.String("active-since"), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	cfg.Filter.ActiveSince = activeSince
+
+	minAccessLevel, err := config.ParseAccessLevel(cmd.String("min-access-level"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	cfg.Filter.MinAccessLevel = minAccessLevel
+
+	return cfg, nil
+}
+
+// loadCredentials fills missing credentials in cfg from the configured
+// credential sources, queried in order
+func loadCredentials(ctx context.Context, cmd *cli.Command, cfg *config.Config, lgr logger.Logger) error {
+	sources := cfg.CredentialSources
+	if len(sources) == 0 {
+		sources = credentials.DefaultSources
+	}
+
+	netrcPath := cmd.String("netrc-file")
+	if netrcPath != "" {
+		if _, err := os.Stat(netrcPath); err != nil {
+			return fmt.Errorf("error loading netrc file: %w", err)
+		}
+	}
+
+	providers := make([]credentials.Provider, 0, len(sources))
+	for _, source := range sources {
+		switch source {
+		case credentials.SourceTokenCommand:
+			providers = append(providers, credentials.NewCommandProvider(cfg.TokenCommand))
+		case credentials.SourceTokenFile:
+			providers = append(providers, credentials.NewFileProvider(cfg.TokenFile))
+		case credentials.SourceNetrc:
+			providers = append(providers, credentials.NewNetrcProvider(netrcPath, lgr))
+		case credentials.SourceGitCredential:
+			providers = append(providers, credentials.NewGitCredentialProvider())
+		default:
+			return fmt.Errorf("unknown credential source: %s", source)
+		}
+	}
+
+	return credentials.NewChain(lgr, providers...).Fill(ctx, cfg)
+}
+
+// loadConfigFile loads the profile selected by --profile from the file given
+// by --config, or from the default config file if it exists
+func loadConfigFile(cmd *cli.Command) (*config.Config, error) {
+	path := cmd.String("config")
+	required := path != ""
+
+	if path == "" {
+		defaultPath, err := config.DefaultFilePath()
+		if err != nil {
+			return nil, nil
+		}
+		path = defaultPath
+	}
+
+	cfg, err := config.LoadFile(path, cmd.String("profile"), required)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config file: %w", err)
+	}
+
+	if cfg == nil && cmd.String("profile") != "" {
+		return nil, fmt.Errorf("profile %s selected but config file %s not found", cmd.String("profile"), path)
+	}
+
+	return cfg, nil
+}
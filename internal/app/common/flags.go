@@ -0,0 +1,76 @@
+package common
+
+import (
+	"time"
+
+	cli "github.com/urfave/cli/v3"
+)
+
+// ProjectFlags returns flags selecting the projects of a command
+func ProjectFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "group",
+			Usage: "select only projects from group and its subgroups (can be repeated)",
+		},
+		&cli.BoolFlag{
+			Name:  "owned",
+			Usage: "select projects owned by the current user",
+		},
+		&cli.BoolFlag{
+			Name:  "starred",
+			Usage: "select projects starred by the current user",
+		},
+		&cli.StringSliceFlag{
+			Name:  "user",
+			Usage: "select personal projects of user (can be repeated)",
+		},
+		&cli.BoolFlag{
+			Name:  "personal",
+			Usage: "select projects in the personal namespace of the current user",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude-group",
+			Usage: "skip projects of groups whose path matches glob, e.g. */sandbox (can be repeated)",
+		},
+		&cli.StringFlag{
+			Name:  "visibility",
+			Usage: "select only projects with visibility: public, internal or private",
+		},
+		&cli.StringFlag{
+			Name:  "archived",
+			Usage: "archived projects: include, exclude or only",
+			Value: "include",
+		},
+		&cli.StringSliceFlag{
+			Name:  "topic",
+			Usage: "select only projects with topic (can be repeated, all topics must match)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "select only projects whose path matches glob (can be repeated)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "skip projects whose path matches glob (can be repeated)",
+		},
+		&cli.StringFlag{
+			Name:  "active-since",
+			Usage: "select only projects with activity since date (2006-01-02) or duration (e.g. 30d, 12h)",
+		},
+		&cli.StringFlag{
+			Name:  "min-access-level",
+			Usage: "select only projects where user has at least role: guest, reporter, developer, maintainer or owner",
+		},
+		&cli.IntFlag{
+			Name:  "retries",
+			Usage: "number of retries of transient API and clone failures",
+			Value: 3,
+		},
+		&cli.DurationFlag{
+			Name:  "retry-backoff",
+			Usage: "delay before the first retry, doubled for each further retry",
+			Value: time.Second,
+		},
+	}
+}
@@ -0,0 +1,107 @@
+package common
+
+import (
+	"context"
+	"strings"
+
+	gl "gitlab.com/gitlab-org/api/client-go"
+
+	config "github.com/adzpm/glone/internal/config"
+	git "github.com/adzpm/glone/internal/git"
+	gitlab "github.com/adzpm/glone/internal/gitlab"
+	logger "github.com/adzpm/glone/internal/logger"
+)
+
+// NewClient connects to the GitLab instance of cfg
+func NewClient(cfg *config.Config, lgr logger.Logger, opts ...gitlab.ClientOption) (*gitlab.Client, error) {
+	opts = append([]gitlab.ClientOption{
+		gitlab.WithLogger(lgr),
+		gitlab.WithRetryPolicy(cfg.RetryPolicy()),
+	}, opts...)
+
+	return gitlab.NewClient(cfg, opts...)
+}
+
+// ListProjects connects to GitLab and returns the projects selected by cfg
+func ListProjects(ctx context.Context, cfg *config.Config, lgr logger.Logger, opts ...gitlab.ClientOption) ([]*gl.Project, error) {
+	// Create GitLab client
+	client, err := NewClient(cfg, lgr, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return SelectedProjects(ctx, client, cfg, lgr)
+}
+
+// SelectedProjects returns the projects selected by cfg
+func SelectedProjects(_ context.Context, client *gitlab.Client, cfg *config.Config, lgr logger.Logger) ([]*gl.Project, error) {
+	// Get project list
+	lgr.Info("Getting project list...")
+	projects, err := client.GetAllProjects(&cfg.Selection, &cfg.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	lgr.Infof("Found projects: %d", len(projects))
+
+	return projects, nil
+}
+
+// GitProjects converts gitlab.Project to git.Project to avoid dependency on gitlab package in git module
+func GitProjects(projects []*gl.Project) []*git.Project {
+	gitProjects := make([]*git.Project, 0, len(projects))
+	for _, glProject := range projects {
+		gitProjects = append(gitProjects, &git.Project{
+			ID:                glProject.ID,
+			Name:              glProject.Name,
+			PathWithNamespace: glProject.PathWithNamespace,
+			HTTPURLToRepo:     glProject.HTTPURLToRepo,
+			SSHURLToRepo:      glProject.SSHURLToRepo,
+			DefaultBranch:     glProject.DefaultBranch,
+			LastActivityAt:    glProject.LastActivityAt,
+		})
+	}
+
+	return gitProjects
+}
+
+// WikiProjects returns the wiki repositories of the projects that have the
+// wiki enabled. Wikis are cloned next to their project at <path>.wiki.
+func WikiProjects(projects []*gl.Project) []*git.Project {
+	var wikis []*git.Project
+	for _, glProject := range projects {
+		if !wikiEnabled(glProject) {
+			continue
+		}
+
+		wikis = append(wikis, &git.Project{
+			ID:                glProject.ID,
+			Name:              glProject.Name + " wiki",
+			PathWithNamespace: glProject.PathWithNamespace + ".wiki",
+			HTTPURLToRepo:     wikiURL(glProject.HTTPURLToRepo),
+			SSHURLToRepo:      wikiURL(glProject.SSHURLToRepo),
+			Wiki:              true,
+		})
+	}
+
+	return wikis
+}
+
+// wikiEnabled reports whether the wiki of a project is enabled
+func wikiEnabled(p *gl.Project) bool {
+	if p.WikiAccessLevel != "" {
+		return p.WikiAccessLevel != gl.DisabledAccessControl
+	}
+
+	// Older GitLab versions only report wiki_enabled
+	return p.WikiEnabled
+}
+
+// wikiURL returns the URL of the wiki repository of a project repository URL
+func wikiURL(repoURL string) string {
+	if repoURL == "" {
+		return ""
+	}
+
+	return strings.TrimSuffix(repoURL, ".git") + ".wiki.git"
+}
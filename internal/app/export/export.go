@@ -0,0 +1,262 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	cli "github.com/urfave/cli/v3"
+	gl "gitlab.com/gitlab-org/api/client-go"
+
+	common "github.com/adzpm/glone/internal/app/common"
+	gitlab "github.com/adzpm/glone/internal/gitlab"
+	logger "github.com/adzpm/glone/internal/logger"
+)
+
+// Exported resources, also the names of their page directories
+const (
+	resourceIssues        = "issues"
+	resourceMergeRequests = "merge_requests"
+	resourceLabels        = "labels"
+	resourceMilestones    = "milestones"
+)
+
+// issueRecord is an exported issue with its discussions, as returned by
+// the API
+type issueRecord struct {
+	Issue       json.RawMessage   `json:"issue"`
+	Discussions []json.RawMessage `json:"discussions"`
+}
+
+// mergeRequestRecord is an exported merge request with its discussions,
+// as returned by the API
+type mergeRequestRecord struct {
+	MergeRequest json.RawMessage   `json:"merge_request"`
+	Discussions  []json.RawMessage `json:"discussions"`
+}
+
+// item holds the fields of an exported issue or merge request needed to
+// fetch its discussions
+type item struct {
+	IID int `json:"iid"`
+}
+
+// counts are the numbers of items exported for a project
+type counts struct {
+	issues        int
+	mergeRequests int
+	labels        int
+	milestones    int
+}
+
+// Run exports the issues, merge requests, discussions, labels and milestones
+// of the selected projects to JSON files in the target directory
+func Run(ctx context.Context, cmd *cli.Command) error {
+	// Create logger instance
+	lgr := logger.New()
+
+	cfg, err := common.LoadConfig(ctx, cmd, lgr)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.NewClient(cfg, lgr)
+	if err != nil {
+		return err
+	}
+
+	projects, err := common.SelectedProjects(ctx, client, cfg, lgr)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			break
+		}
+
+		dir := filepath.Join(cfg.TargetDir, Dir, project.PathWithNamespace)
+		n, err := exportProject(ctx, client, project, dir, cmd.Bool("full"))
+		if err != nil {
+			lgr.Errorf("Error exporting %s: %v", project.PathWithNamespace, err)
+			failed++
+			continue
+		}
+
+		lgr.Infof("Exported %s: %d issues, %d merge requests, %d labels, %d milestones",
+			project.PathWithNamespace, n.issues, n.mergeRequests, n.labels, n.milestones)
+	}
+
+	lgr.Infof("Completed. Exported: %d, Errors: %d", len(projects)-failed, failed)
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("interrupted: %w", err)
+	}
+
+	if failed > 0 && !cmd.Bool("ignore-errors") {
+		return fmt.Errorf("%d projects failed", failed)
+	}
+
+	return nil
+}
+
+// exportProject exports a project to dir. Issues and merge requests are
+// exported incrementally unless full is set; labels and milestones are
+// exported completely every time.
+func exportProject(ctx context.Context, client *gitlab.Client, project *gl.Project, dir string, full bool) (counts, error) {
+	var n counts
+
+	st, err := loadState(dir)
+	if err != nil {
+		return n, err
+	}
+
+	// A different project may have been exported to the same path before
+	if st == nil || st.ProjectID != project.ID || full {
+		st = &projectState{
+			Version:   version,
+			ProjectID: project.ID,
+			Resources: make(map[string]*resourceState),
+		}
+	}
+	st.Path = project.PathWithNamespace
+
+	startedAt := time.Now()
+
+	if featureEnabled(project.IssuesAccessLevel, project.IssuesEnabled) {
+		n.issues, err = exportUpdated(dir, resourceIssues, st, startedAt, func(updatedAfter *time.Time, fn func([]issueRecord) error) error {
+			return client.ListIssues(ctx, project.ID, updatedAfter, func(issues []json.RawMessage) error {
+				records := make([]issueRecord, 0, len(issues))
+				for _, issue := range issues {
+					var i item
+					if err := json.Unmarshal(issue, &i); err != nil {
+						return fmt.Errorf("error decoding issue: %w", err)
+					}
+
+					discussions, err := client.IssueDiscussions(ctx, project.ID, i.IID)
+					if err != nil {
+						return fmt.Errorf("error getting discussions of issue #%d: %w", i.IID, err)
+					}
+					records = append(records, issueRecord{Issue: issue, Discussions: discussions})
+				}
+				return fn(records)
+			})
+		})
+		if err != nil {
+			return n, fmt.Errorf("error exporting issues: %w", err)
+		}
+	}
+
+	if featureEnabled(project.MergeRequestsAccessLevel, project.MergeRequestsEnabled) {
+		n.mergeRequests, err = exportUpdated(dir, resourceMergeRequests, st, startedAt, func(updatedAfter *time.Time, fn func([]mergeRequestRecord) error) error {
+			return client.ListMergeRequests(ctx, project.ID, updatedAfter, func(mergeRequests []json.RawMessage) error {
+				records := make([]mergeRequestRecord, 0, len(mergeRequests))
+				for _, mr := range mergeRequests {
+					var i item
+					if err := json.Unmarshal(mr, &i); err != nil {
+						return fmt.Errorf("error decoding merge request: %w", err)
+					}
+
+					discussions, err := client.MergeRequestDiscussions(ctx, project.ID, i.IID)
+					if err != nil {
+						return fmt.Errorf("error getting discussions of merge request !%d: %w", i.IID, err)
+					}
+					records = append(records, mergeRequestRecord{MergeRequest: mr, Discussions: discussions})
+				}
+				return fn(records)
+			})
+		})
+		if err != nil {
+			return n, fmt.Errorf("error exporting merge requests: %w", err)
+		}
+	}
+
+	n.labels, err = exportAll(dir, resourceLabels, st, func(fn func([]json.RawMessage) error) error {
+		return client.ListLabels(ctx, project.ID, fn)
+	})
+	if err != nil {
+		return n, fmt.Errorf("error exporting labels: %w", err)
+	}
+
+	n.milestones, err = exportAll(dir, resourceMilestones, st, func(fn func([]json.RawMessage) error) error {
+		return client.ListMilestones(ctx, project.ID, fn)
+	})
+	if err != nil {
+		return n, fmt.Errorf("error exporting milestones: %w", err)
+	}
+
+	st.ExportedAt = &startedAt
+	return n, st.save(dir)
+}
+
+// exportUpdated appends the items of a resource updated since its last
+// export as new page files, so the latest version of an item is in the page
+// with the highest number. The state is saved once all pages are written;
+// the pages of an interrupted export are replaced by the next one.
+func exportUpdated[T any](dir string, resource string, st *projectState, startedAt time.Time, list func(updatedAfter *time.Time, fn func([]T) error) error) (int, error) {
+	res := st.resource(resource)
+	page := res.Pages
+	total := 0
+
+	err := list(res.UpdatedAfter, func(items []T) error {
+		if len(items) == 0 {
+			return nil
+		}
+
+		page++
+		total += len(items)
+		return writeJSON(pageFile(dir, resource, page), items)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := removePages(dir, resource, page); err != nil {
+		return 0, err
+	}
+	res.Pages = page
+	res.UpdatedAfter = &startedAt
+
+	return total, st.save(dir)
+}
+
+// exportAll replaces the page files of a resource with all its items
+func exportAll[T any](dir string, resource string, st *projectState, list func(fn func([]T) error) error) (int, error) {
+	res := st.resource(resource)
+	page := 0
+	total := 0
+
+	err := list(func(items []T) error {
+		if len(items) == 0 {
+			return nil
+		}
+
+		page++
+		total += len(items)
+		return writeJSON(pageFile(dir, resource, page), items)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := removePages(dir, resource, page); err != nil {
+		return 0, err
+	}
+	res.Pages = page
+
+	return total, st.save(dir)
+}
+
+// featureEnabled reports whether a project feature such as issues is
+// enabled, given its access level and the enabled flag reported by older
+// GitLab versions
+func featureEnabled(accessLevel gl.AccessControlValue, enabled bool) bool {
+	if accessLevel != "" {
+		return accessLevel != gl.DisabledAccessControl
+	}
+
+	return enabled
+}
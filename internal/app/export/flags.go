@@ -0,0 +1,21 @@
+package export
+
+import (
+	cli "github.com/urfave/cli/v3"
+
+	common "github.com/adzpm/glone/internal/app/common"
+)
+
+// Flags returns flags for the export command
+func Flags() []cli.Flag {
+	return append(common.ProjectFlags(), []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "full",
+			Usage: "export all items again, also those not updated since the last export",
+		},
+		&cli.BoolFlag{
+			Name:  "ignore-errors",
+			Usage: "exit with zero status even if some projects failed",
+		},
+	}...)
+}
@@ -0,0 +1,124 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Dir is the directory holding the exported metadata of all projects,
+// relative to the target directory
+const Dir = ".glone-meta"
+
+// stateFile is the name of the export state file in the directory of a project
+const stateFile = "export.json"
+
+// version is the version of the export state file format
+const version = 1
+
+// resourceState is the recorded state of a single exported resource
+type resourceState struct {
+	// UpdatedAfter is the start of the last complete export; later runs only
+	// fetch items updated since then
+	UpdatedAfter *time.Time `json:"updated_after,omitempty"`
+	// Pages is the number of page files written
+	Pages int `json:"pages"`
+}
+
+// projectState is the recorded state of the export of a project
+type projectState struct {
+	Version    int                       `json:"version"`
+	ProjectID  int                       `json:"project_id"`
+	Path       string                    `json:"path"`
+	ExportedAt *time.Time                `json:"exported_at,omitempty"`
+	Resources  map[string]*resourceState `json:"resources"`
+}
+
+// loadState reads the export state in dir. A missing state file yields nil.
+func loadState(dir string) (*projectState, error) {
+	file := filepath.Join(dir, stateFile)
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export state: %w", err)
+	}
+
+	var s projectState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse export state %s: %w", file, err)
+	}
+
+	if s.Version > version {
+		return nil, fmt.Errorf("export state %s has unsupported version %d", file, s.Version)
+	}
+
+	if s.Resources == nil {
+		s.Resources = make(map[string]*resourceState)
+	}
+
+	return &s, nil
+}
+
+// resource returns the state of a resource, creating it if necessary
+func (s *projectState) resource(name string) *resourceState {
+	r, ok := s.Resources[name]
+	if !ok {
+		r = &resourceState{}
+		s.Resources[name] = r
+	}
+
+	return r
+}
+
+// save writes the export state to dir
+func (s *projectState) save(dir string) error {
+	if err := writeJSON(filepath.Join(dir, stateFile), s); err != nil {
+		return fmt.Errorf("failed to write export state: %w", err)
+	}
+
+	return nil
+}
+
+// pageFile returns the path of a page file of a resource
+func pageFile(dir string, resource string, page int) string {
+	return filepath.Join(dir, resource, fmt.Sprintf("%04d.json", page))
+}
+
+// removePages removes the page files of a resource after the last one
+// written by the current export, left by earlier or interrupted exports
+func removePages(dir string, resource string, last int) error {
+	for page := last + 1; ; page++ {
+		err := os.Remove(pageFile(dir, resource, page))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeJSON writes v as indented JSON to file, replacing it atomically
+func writeJSON(file string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, file)
+}
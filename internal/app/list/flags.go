@@ -0,0 +1,22 @@
+package list
+
+import (
+	cli "github.com/urfave/cli/v3"
+
+	common "github.com/adzpm/glone/internal/app/common"
+)
+
+// Flags returns flags for the list command
+func Flags() []cli.Flag {
+	return append(common.ProjectFlags(), []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "output format: table, json, csv or paths",
+			Value: FormatTable,
+		},
+		&cli.BoolFlag{
+			Name:  "mirror",
+			Usage: "report the local status of mirror clones at <path>.git",
+		},
+	}...)
+}
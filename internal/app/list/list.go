@@ -0,0 +1,188 @@
+package list
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	cli "github.com/urfave/cli/v3"
+	gl "gitlab.com/gitlab-org/api/client-go"
+
+	common "github.com/adzpm/glone/internal/app/common"
+	git "github.com/adzpm/glone/internal/git"
+	gitlab "github.com/adzpm/glone/internal/gitlab"
+	logger "github.com/adzpm/glone/internal/logger"
+)
+
+// Output formats
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+	FormatPaths = "paths"
+)
+
+// Project is a single listed project
+type Project struct {
+	PathWithNamespace string     `json:"path_with_namespace"`
+	Visibility        string     `json:"visibility"`
+	Archived          bool       `json:"archived"`
+	DefaultBranch     string     `json:"default_branch"`
+	LastActivityAt    *time.Time `json:"last_activity_at,omitempty"`
+	Size              *int64     `json:"size,omitempty"`
+	Local             string     `json:"local"`
+}
+
+// Run prints the projects that clone and sync would process,
+// together with their local status in the target directory
+func Run(ctx context.Context, cmd *cli.Command) error {
+	// Log to stderr, stdout is reserved for the listing
+	lgr := logger.New(logger.WithOutput(os.Stderr))
+
+	format := cmd.String("format")
+	switch format {
+	case FormatTable, FormatJSON, FormatCSV, FormatPaths:
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	cfg, err := common.LoadConfig(ctx, cmd, lgr)
+	if err != nil {
+		return err
+	}
+
+	glProjects, err := common.ListProjects(ctx, cfg, lgr, gitlab.WithStatistics(true))
+	if err != nil {
+		return err
+	}
+
+	// The cloner resolves local paths the same way clone and sync do
+	cloner, err := git.NewCloner(git.WithMirror(cfg.Mirror))
+	if err != nil {
+		return err
+	}
+
+	projects := make([]Project, 0, len(glProjects))
+	for _, glProject := range glProjects {
+		projectPath := cloner.ProjectPath(cfg.TargetDir, &git.Project{PathWithNamespace: glProject.PathWithNamespace})
+		projects = append(projects, newProject(glProject, git.LocalStatus(projectPath)))
+	}
+
+	out := cmd.Root().Writer
+	switch format {
+	case FormatJSON:
+		return writeJSON(out, projects)
+	case FormatCSV:
+		return writeCSV(out, projects)
+	case FormatPaths:
+		return writePaths(out, projects)
+	default:
+		return writeTable(out, projects)
+	}
+}
+
+func newProject(p *gl.Project, local string) Project {
+	project := Project{
+		PathWithNamespace: p.PathWithNamespace,
+		Visibility:        string(p.Visibility),
+		Archived:          p.Archived,
+		DefaultBranch:     p.DefaultBranch,
+		LastActivityAt:    p.LastActivityAt,
+		Local:             local,
+	}
+
+	// Statistics are only returned when requested and permitted
+	if p.Statistics != nil {
+		project.Size = gl.Ptr(p.Statistics.RepositorySize)
+	}
+
+	return project
+}
+
+// columns returns the table and CSV cells of a project
+func (p Project) columns() []string {
+	lastActivity := ""
+	if p.LastActivityAt != nil {
+		lastActivity = p.LastActivityAt.UTC().Format(time.RFC3339)
+	}
+
+	size := ""
+	if p.Size != nil {
+		size = strconv.FormatInt(*p.Size, 10)
+	}
+
+	return []string{
+		p.PathWithNamespace,
+		p.Visibility,
+		strconv.FormatBool(p.Archived),
+		p.DefaultBranch,
+		lastActivity,
+		size,
+		p.Local,
+	}
+}
+
+var header = []string{"path_with_namespace", "visibility", "archived", "default_branch", "last_activity_at", "size", "local"}
+
+func writeJSON(w io.Writer, projects []Project) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(projects)
+}
+
+func writeCSV(w io.Writer, projects []Project) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, p := range projects {
+		if err := cw.Write(p.columns()); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writePaths(w io.Writer, projects []Project) error {
+	for _, p := range projects {
+		if _, err := fmt.Fprintln(w, p.PathWithNamespace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeTable(w io.Writer, projects []Project) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	row := func(cells []string) {
+		for i, cell := range cells {
+			if cell == "" {
+				cell = "-"
+			}
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, cell)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	row(header)
+	for _, p := range projects {
+		row(p.columns())
+	}
+
+	return tw.Flush()
+}
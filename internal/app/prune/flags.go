@@ -0,0 +1,43 @@
+package prune
+
+import (
+	cli "github.com/urfave/cli/v3"
+
+	common "github.com/adzpm/glone/internal/app/common"
+)
+
+// Flags returns flags for the prune command
+func Flags() []cli.Flag {
+	return append(common.ProjectFlags(), []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "prune without asking for confirmation",
+		},
+		&cli.BoolFlag{
+			Name:  "archive",
+			Usage: "move orphaned repositories to .glone/archive instead of deleting them",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "also prune repositories with local changes or unpushed commits",
+		},
+	}...)
+}
+
+// unsupportedFlags are project flags prune rejects: projects that are not
+// selected or are filtered out still exist, so their clones are no orphans
+var unsupportedFlags = []string{
+	"owned",
+	"starred",
+	"user",
+	"personal",
+	"exclude-group",
+	"visibility",
+	"archived",
+	"topic",
+	"include",
+	"exclude",
+	"active-since",
+	"min-access-level",
+}
@@ -0,0 +1,239 @@
+package prune
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	cli "github.com/urfave/cli/v3"
+
+	common "github.com/adzpm/glone/internal/app/common"
+	config "github.com/adzpm/glone/internal/config"
+	git "github.com/adzpm/glone/internal/git"
+	logger "github.com/adzpm/glone/internal/logger"
+	state "github.com/adzpm/glone/internal/state"
+)
+
+// orphan is a local repository without a project on GitLab
+type orphan struct {
+	path string
+	// unsafe is the reason the repository must not be pruned without --force
+	unsafe string
+}
+
+// Run removes or archives local repositories whose projects no longer exist
+// on GitLab, e.g. because they were deleted, renamed or transferred
+func Run(ctx context.Context, cmd *cli.Command) error {
+	// Create logger instance
+	lgr := logger.New()
+
+	for _, name := range unsupportedFlags {
+		if cmd.IsSet(name) {
+			return fmt.Errorf("--%s is not supported by prune, only --group limits the projects", name)
+		}
+	}
+
+	cfg, err := common.LoadConfig(ctx, cmd, lgr)
+	if err != nil {
+		return err
+	}
+
+	// Filtered out projects still exist, so only the groups limit the selection
+	if selection := cfg.Selection; selection.Owned || selection.Starred || selection.Personal || len(selection.Users) > 0 {
+		lgr.Warn("Ignoring the owned, starred, users and personal selection of the profile")
+	}
+	cfg.Selection = config.Selection{Groups: cfg.Selection.Groups}
+	cfg.Filter = config.Filter{Archived: config.ArchivedInclude}
+
+	st, err := state.Load(cfg.TargetDir)
+	if err != nil {
+		return err
+	}
+
+	projects, err := common.ListProjects(ctx, cfg, lgr)
+	if err != nil {
+		return err
+	}
+
+	// Clones and mirrors of a project and its wiki are all known
+	known := make(map[string]bool, 4*len(projects))
+	for _, project := range projects {
+		projectPath := filepath.Join(cfg.TargetDir, project.PathWithNamespace)
+		for _, p := range []string{projectPath, projectPath + ".wiki"} {
+			known[p] = true
+			known[p+".git"] = true
+		}
+	}
+
+	roots := []string{cfg.TargetDir}
+	if len(cfg.Selection.Groups) > 0 {
+		roots = roots[:0]
+		for _, group := range cfg.Selection.Groups {
+			roots = append(roots, filepath.Join(cfg.TargetDir, group))
+		}
+	}
+
+	var repos []string
+	for _, root := range roots {
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			continue
+		}
+
+		found, err := git.FindRepositories(root)
+		if err != nil {
+			return fmt.Errorf("error searching repositories in %s: %w", root, err)
+		}
+
+		// Nested groups would be searched twice
+		for _, repo := range found {
+			if !slices.Contains(repos, repo) {
+				repos = append(repos, repo)
+			}
+		}
+	}
+
+	// Clones of other GitLab instances sharing the target directory are not
+	// listed, only repositories recorded for this host may be pruned then
+	if recorded := recordedRepositories(st, cfg.GitLabHost, cfg.TargetDir); recorded != nil {
+		lgr.Infof("Target directory is shared with other GitLab hosts, pruning only repositories recorded for %s", cfg.GitLabHost)
+		repos = slices.DeleteFunc(repos, func(repo string) bool {
+			return !recorded[repo]
+		})
+	}
+
+	orphans := findOrphans(lgr, repos, known)
+	if len(orphans) == 0 {
+		lgr.Info("No orphaned repositories found")
+		return nil
+	}
+
+	force := cmd.Bool("force")
+	var prunable []orphan
+	for _, o := range orphans {
+		rel, _ := filepath.Rel(cfg.TargetDir, o.path)
+		switch {
+		case o.unsafe == "":
+			lgr.Infof("Orphaned: %s", rel)
+			prunable = append(prunable, o)
+		case force:
+			lgr.Warnf("Orphaned: %s (%s, forced)", rel, o.unsafe)
+			prunable = append(prunable, o)
+		default:
+			lgr.Warnf("Orphaned: %s (%s, refusing to prune without --force)", rel, o.unsafe)
+		}
+	}
+
+	if len(prunable) == 0 {
+		return nil
+	}
+
+	archive := cmd.Bool("archive")
+	verb := "Delete"
+	if archive {
+		verb = "Archive"
+	}
+
+	if !cmd.Bool("yes") && !confirm(cmd.Root().Reader, cmd.Root().Writer, fmt.Sprintf("%s %d repositories?", verb, len(prunable))) {
+		lgr.Info("Aborted")
+		return nil
+	}
+
+	errorCount := 0
+	for _, o := range prunable {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("interrupted: %w", err)
+		}
+
+		if archive {
+			archivePath, err := git.ArchiveRepository(o.path, cfg.TargetDir)
+			if err != nil {
+				lgr.Errorf("Error archiving %s: %v", o.path, err)
+				errorCount++
+				continue
+			}
+			lgr.Infof("Archived %s to %s", o.path, archivePath)
+		} else {
+			if err := git.RemoveRepository(o.path, cfg.TargetDir); err != nil {
+				lgr.Errorf("Error removing %s: %v", o.path, err)
+				errorCount++
+				continue
+			}
+			lgr.Infof("Removed %s", o.path)
+		}
+	}
+
+	lgr.Infof("Completed. Pruned: %d, Refused: %d, Errors: %d",
+		len(prunable)-errorCount, len(orphans)-len(prunable), errorCount)
+
+	if errorCount > 0 {
+		return fmt.Errorf("%d repositories could not be pruned", errorCount)
+	}
+
+	return nil
+}
+
+// recordedRepositories returns the possible repository paths of the projects
+// recorded in the state for host, or nil if no other host is recorded
+func recordedRepositories(st *state.State, host string, targetDir string) map[string]bool {
+	shared := false
+	for _, h := range st.Hosts() {
+		// Projects recorded before hosts were recorded have no host
+		if h != "" && h != host {
+			shared = true
+		}
+	}
+	if !shared {
+		return nil
+	}
+
+	recorded := make(map[string]bool)
+	for _, project := range st.Projects(host) {
+		projectPath := filepath.Join(targetDir, project.Path)
+		for _, p := range []string{projectPath, projectPath + ".wiki"} {
+			recorded[p] = true
+			recorded[p+".git"] = true
+		}
+	}
+
+	return recorded
+}
+
+// findOrphans returns the repositories that are not known projects,
+// together with the reason it is unsafe to prune them
+func findOrphans(lgr logger.Logger, repos []string, known map[string]bool) []orphan {
+	var orphans []orphan
+	for _, repo := range repos {
+		if known[repo] {
+			continue
+		}
+
+		o := orphan{path: repo}
+		if git.LocalStatus(repo) == git.LocalDirty {
+			o.unsafe = "local changes"
+		} else if branches, err := git.UnpushedBranches(repo); err != nil {
+			lgr.Errorf("Error checking %s: %v", repo, err)
+			o.unsafe = "unknown state"
+		} else if len(branches) > 0 {
+			o.unsafe = "unpushed commits on " + strings.Join(branches, ", ")
+		}
+
+		orphans = append(orphans, o)
+	}
+
+	return orphans
+}
+
+// confirm asks a yes/no question and reports whether it was answered with yes
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,65 @@
+package scrub
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	cli "github.com/urfave/cli/v3"
+
+	git "github.com/adzpm/glone/internal/git"
+	logger "github.com/adzpm/glone/internal/logger"
+)
+
+// Run removes access tokens embedded in remote URLs of all repositories
+// in the target directory
+func Run(ctx context.Context, cmd *cli.Command) error {
+	// Create logger instance
+	lgr := logger.New()
+
+	targetDir := cmd.Args().First()
+	if targetDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		targetDir = wd
+	}
+
+	repos, err := git.FindRepositories(targetDir)
+	if err != nil {
+		return fmt.Errorf("error searching repositories in %s: %w", targetDir, err)
+	}
+
+	lgr.Infof("Found repositories: %d", len(repos))
+
+	scrubbedCount := 0
+	errorCount := 0
+
+	for _, repo := range repos {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("interrupted: %w", err)
+		}
+
+		remotes, err := git.ScrubRemoteCredentials(repo)
+		if err != nil {
+			lgr.Errorf("Error scrubbing %s: %v", repo, err)
+			errorCount++
+			continue
+		}
+
+		if len(remotes) > 0 {
+			lgr.Infof("Removed credentials from %s (remotes: %s)", repo, strings.Join(remotes, ", "))
+			scrubbedCount++
+		}
+	}
+
+	lgr.Infof("Completed. Scrubbed: %d, Errors: %d", scrubbedCount, errorCount)
+
+	if errorCount > 0 {
+		return fmt.Errorf("%d repositories could not be scrubbed", errorCount)
+	}
+
+	return nil
+}
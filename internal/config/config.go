@@ -1,12 +1,103 @@
 package config
 
+import (
+	"time"
+
+	retry "github.com/adzpm/glone/internal/retry"
+)
+
+// Field identifies settings that can be set explicitly to their zero value,
+// e.g. with --mirror=false or retries: 0 in a profile
+type Field uint
+
+// Settings tracked in Config.Set
+const (
+	FieldSelection Field = 1 << iota
+	FieldMirror
+	FieldWikis
+	FieldLFS
+	FieldLFSFetchOnly
+	FieldRecurseSubmodules
+	FieldSingleBranch
+	FieldDepth
+	FieldRetries
+)
+
 // Config holds the application configuration
 type Config struct {
 	GitLabHost  string
 	GitLabUser  string
 	GitLabToken string
-	Group       string
-	TargetDir   string
+
+	// TokenCommand is a shell command printing the access token
+	TokenCommand string
+	// TokenFile is a file holding the access token
+	TokenFile string
+	// CredentialSources is the order in which credential sources are queried
+	CredentialSources []string
+
+	Selection Selection
+	TargetDir string
+	Jobs      int
+
+	Protocol         string
+	SSHKey           string
+	SSHKeyPassphrase string
+
+	// Retries is the number of retries of transient failures; zero disables
+	// them if FieldRetries is set
+	Retries int
+	// RetryBackoff is the delay before the first retry, doubled for each further one
+	RetryBackoff time.Duration
+
+	// Mirror makes bare mirror clones with all refs
+	Mirror bool
+	// Wikis clones the wiki repositories of projects as well
+	Wikis bool
+
+	// LFS downloads Git LFS objects of cloned projects
+	LFS bool
+	// LFSFetchOnly only stores LFS objects without replacing pointer files
+	LFSFetchOnly bool
+
+	// RecurseSubmodules initializes and updates the submodules of projects
+	RecurseSubmodules bool
+
+	// Depth limits clones to the given number of commits
+	Depth int
+	// SingleBranch clones only the checked-out branch
+	SingleBranch bool
+	// Branch is the branch to check out, "default" for the default branch
+	Branch string
+	// CloneFilter is a partial clone filter such as blob:none
+	CloneFilter string
+	// Unshallow fetches the full history of shallow clones when syncing
+	Unshallow bool
+
+	// OnConflict is what to do with project paths that exist but are not
+	// git repositories: skip, backup, remove or fail
+	OnConflict string
+
+	Filter Filter
+
+	// Set holds the fields set explicitly, even if to their zero value. Other
+	// settings count as set if they are not empty.
+	Set Field
+}
+
+// Defaults returns the configuration used for values that are not set
+// by flags, environment variables, the config file or .netrc
+func Defaults() *Config {
+	return &Config{
+		Jobs:         1,
+		Protocol:     "https",
+		Retries:      3,
+		RetryBackoff: time.Second,
+		OnConflict:   "skip",
+		Filter: Filter{
+			Archived: ArchivedInclude,
+		},
+	}
 }
 
 // Validate checks that all required fields are set
@@ -26,7 +117,9 @@ func (c *Config) Validate() error {
 	return nil
 }
 
-// Merge merges non-empty values from other into c
+// Merge merges the values set in other into c, so values already set in c
+// take precedence. Configs are merged from the highest to the lowest
+// precedence source: flags and environment, config file, .netrc, defaults.
 func (c *Config) Merge(other *Config) {
 	if other == nil {
 		return
@@ -43,4 +136,114 @@ func (c *Config) Merge(other *Config) {
 	if c.GitLabToken == "" && other.GitLabToken != "" {
 		c.GitLabToken = other.GitLabToken
 	}
+
+	if c.TokenCommand == "" && other.TokenCommand != "" {
+		c.TokenCommand = other.TokenCommand
+	}
+
+	if c.TokenFile == "" && other.TokenFile != "" {
+		c.TokenFile = other.TokenFile
+	}
+
+	if len(c.CredentialSources) == 0 && len(other.CredentialSources) > 0 {
+		c.CredentialSources = other.CredentialSources
+	}
+
+	// A selection is never combined with one from another config source
+	if c.inherits(other, FieldSelection, !c.Selection.IsEmpty(), !other.Selection.IsEmpty()) {
+		c.Selection = other.Selection
+	}
+
+	if c.TargetDir == "" && other.TargetDir != "" {
+		c.TargetDir = other.TargetDir
+	}
+
+	if c.Jobs == 0 && other.Jobs != 0 {
+		c.Jobs = other.Jobs
+	}
+
+	if c.Protocol == "" && other.Protocol != "" {
+		c.Protocol = other.Protocol
+	}
+
+	if c.SSHKey == "" && other.SSHKey != "" {
+		c.SSHKey = other.SSHKey
+	}
+
+	if c.SSHKeyPassphrase == "" && other.SSHKeyPassphrase != "" {
+		c.SSHKeyPassphrase = other.SSHKeyPassphrase
+	}
+
+	if c.inherits(other, FieldRetries, c.Retries != 0, other.Retries != 0) {
+		c.Retries = other.Retries
+	}
+
+	if c.RetryBackoff == 0 && other.RetryBackoff != 0 {
+		c.RetryBackoff = other.RetryBackoff
+	}
+
+	if c.inherits(other, FieldMirror, c.Mirror, other.Mirror) {
+		c.Mirror = other.Mirror
+	}
+
+	if c.inherits(other, FieldWikis, c.Wikis, other.Wikis) {
+		c.Wikis = other.Wikis
+	}
+
+	if c.inherits(other, FieldLFS, c.LFS, other.LFS) {
+		c.LFS = other.LFS
+	}
+
+	if c.inherits(other, FieldLFSFetchOnly, c.LFSFetchOnly, other.LFSFetchOnly) {
+		c.LFSFetchOnly = other.LFSFetchOnly
+	}
+
+	if c.inherits(other, FieldRecurseSubmodules, c.RecurseSubmodules, other.RecurseSubmodules) {
+		c.RecurseSubmodules = other.RecurseSubmodules
+	}
+
+	if c.inherits(other, FieldDepth, c.Depth != 0, other.Depth != 0) {
+		c.Depth = other.Depth
+	}
+
+	if c.inherits(other, FieldSingleBranch, c.SingleBranch, other.SingleBranch) {
+		c.SingleBranch = other.SingleBranch
+	}
+
+	if c.Branch == "" && other.Branch != "" {
+		c.Branch = other.Branch
+	}
+
+	if c.CloneFilter == "" && other.CloneFilter != "" {
+		c.CloneFilter = other.CloneFilter
+	}
+
+	if c.OnConflict == "" && other.OnConflict != "" {
+		c.OnConflict = other.OnConflict
+	}
+
+	c.Filter.Merge(&other.Filter)
+}
+
+// inherits reports whether a field is taken from other because it is set in
+// other but not in c, and marks it as set in c. set and otherSet report
+// whether the field has a non-zero value in c and other.
+func (c *Config) inherits(other *Config, field Field, set bool, otherSet bool) bool {
+	if set || c.Set&field != 0 || (!otherSet && other.Set&field == 0) {
+		return false
+	}
+
+	c.Set |= field
+	return true
+}
+
+// RetryPolicy returns the retry policy for API requests and git operations
+func (c *Config) RetryPolicy() retry.Policy {
+	policy := retry.DefaultPolicy()
+	policy.Retries = max(c.Retries, 0)
+	if c.RetryBackoff > 0 {
+		policy.Backoff = c.RetryBackoff
+	}
+
+	return policy
 }
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestConfigMerge(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags *Config
+		file  *Config
+		check func(c *Config) bool
+	}{
+		{
+			name:  "file sets a flag that was not given",
+			flags: &Config{},
+			file:  &Config{Mirror: true, Set: FieldMirror},
+			check: func(c *Config) bool { return c.Mirror },
+		},
+		{
+			name:  "flag false overrides file true",
+			flags: &Config{Mirror: false, Set: FieldMirror},
+			file:  &Config{Mirror: true, Set: FieldMirror},
+			check: func(c *Config) bool { return !c.Mirror },
+		},
+		{
+			name:  "flag false overrides file true without mark",
+			flags: &Config{LFS: false, Set: FieldLFS},
+			file:  &Config{LFS: true},
+			check: func(c *Config) bool { return !c.LFS },
+		},
+		{
+			name:  "flag true overrides file false",
+			flags: &Config{Wikis: true},
+			file:  &Config{Wikis: false, Set: FieldWikis},
+			check: func(c *Config) bool { return c.Wikis },
+		},
+		{
+			name:  "file retries zero overrides default",
+			flags: &Config{},
+			file:  &Config{Retries: 0, Set: FieldRetries},
+			check: func(c *Config) bool { return c.Retries == 0 && c.RetryPolicy().Retries == 0 },
+		},
+		{
+			name:  "flag retries overrides file",
+			flags: &Config{Retries: 0, Set: FieldRetries},
+			file:  &Config{Retries: 5, Set: FieldRetries},
+			check: func(c *Config) bool { return c.Retries == 0 },
+		},
+		{
+			name:  "default retries",
+			flags: &Config{},
+			file:  &Config{},
+			check: func(c *Config) bool { return c.Retries == 3 },
+		},
+		{
+			name:  "flag depth zero overrides file",
+			flags: &Config{Depth: 0, Set: FieldDepth},
+			file:  &Config{Depth: 1, Set: FieldDepth},
+			check: func(c *Config) bool { return c.Depth == 0 },
+		},
+		{
+			name:  "explicit empty selection overrides file",
+			flags: &Config{Set: FieldSelection},
+			file:  &Config{Selection: Selection{Groups: []string{"backend"}, Owned: true}},
+			check: func(c *Config) bool { return c.Selection.IsEmpty() },
+		},
+		{
+			name:  "selection is not combined",
+			flags: &Config{Selection: Selection{Starred: true}},
+			file:  &Config{Selection: Selection{Groups: []string{"backend"}}},
+			check: func(c *Config) bool { return c.Selection.Starred && len(c.Selection.Groups) == 0 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.flags
+			c.Merge(tt.file)
+			c.Merge(Defaults())
+
+			if !tt.check(c) {
+				t.Errorf("Merge() = %+v", c)
+			}
+		})
+	}
+}
@@ -8,4 +8,13 @@ var (
 	ErrMissingGitLabHost  = errors.New("gitlab-host is required")
 	ErrMissingGitLabUser  = errors.New("gitlab-user is required")
 	ErrMissingGitLabToken = errors.New("gitlab-token is required")
+
+	ErrInvalidVisibility  = errors.New("visibility must be public, internal or private")
+	ErrInvalidArchived    = errors.New("archived must be include, exclude or only")
+	ErrInvalidPattern     = errors.New("invalid path pattern")
+	ErrInvalidActiveSince = errors.New("active-since must be a date or a duration")
+	ErrInvalidAccessLevel = errors.New("min-access-level must be a role name or a number")
+
+	ErrUnknownProfile    = errors.New("profile not found in config file")
+	ErrNoProfileSelected = errors.New("config file has multiple profiles, select one with --profile or default_profile")
 )
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// File is the layout of the glone configuration file
+type File struct {
+	// DefaultProfile is used when no profile is selected explicitly
+	DefaultProfile string `yaml:"default_profile"`
+	// Profiles are named sets of settings, e.g. one per GitLab instance
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Profile holds the settings of a single configuration profile
+type Profile struct {
+	Host string `yaml:"host"`
+	User string `yaml:"user"`
+	// Token is the access token itself; prefer TokenEnv
+	Token string `yaml:"token"`
+	// TokenEnv is the name of an environment variable holding the token
+	TokenEnv string `yaml:"token_env"`
+	// TokenCommand is a shell command printing the token
+	TokenCommand string `yaml:"token_command"`
+	// TokenFile is a file holding the token
+	TokenFile string `yaml:"token_file"`
+	// CredentialSources is the order in which credential sources are queried
+	CredentialSources []string `yaml:"credential_sources"`
+
+	Group     string   `yaml:"group"`
+	Groups    []string `yaml:"groups"`
+	Users     []string `yaml:"users"`
+	Owned     bool     `yaml:"owned"`
+	Starred   bool     `yaml:"starred"`
+	Personal  bool     `yaml:"personal"`
+	TargetDir string   `yaml:"target_dir"`
+	Jobs      int      `yaml:"jobs"`
+	Protocol  string   `yaml:"protocol"`
+	SSHKey    string   `yaml:"ssh_key"`
+	Mirror    *bool    `yaml:"mirror"`
+	Wikis     *bool    `yaml:"wikis"`
+
+	LFS          *bool `yaml:"lfs"`
+	LFSFetchOnly *bool `yaml:"lfs_fetch_only"`
+
+	RecurseSubmodules *bool `yaml:"recurse_submodules"`
+
+	Retries      *int          `yaml:"retries"`
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+
+	Depth        *int   `yaml:"depth"`
+	SingleBranch *bool  `yaml:"single_branch"`
+	Branch       string `yaml:"branch"`
+	CloneFilter  string `yaml:"clone_filter"`
+
+	OnConflict string `yaml:"on_conflict"`
+
+	Filter ProfileFilter `yaml:"filter"`
+}
+
+// ProfileFilter holds project filters of a profile
+type ProfileFilter struct {
+	Visibility     string   `yaml:"visibility"`
+	Archived       string   `yaml:"archived"`
+	Topics         []string `yaml:"topics"`
+	Include        []string `yaml:"include"`
+	Exclude        []string `yaml:"exclude"`
+	ExcludeGroups  []string `yaml:"exclude_groups"`
+	ActiveSince    string   `yaml:"active_since"`
+	MinAccessLevel string   `yaml:"min_access_level"`
+}
+
+// DefaultFilePath returns the default configuration file path,
+// e.g. ~/.config/glone/config.yaml
+func DefaultFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "glone", "config.yaml"), nil
+}
+
+// LoadFile reads the configuration file at path and returns the selected
+// profile as a Config. If required is false, a missing file is not an
+// error and nil is returned. An empty profile name selects the file's
+// default profile, or the only profile if there is exactly one.
+func LoadFile(path string, profile string, required bool) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	name, err := file.selectProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	return file.Profiles[name].toConfig(time.Now())
+}
+
+// selectProfile returns the name of the profile to use
+func (f *File) selectProfile(profile string) (string, error) {
+	if profile == "" {
+		profile = f.DefaultProfile
+	}
+
+	if profile != "" {
+		if _, ok := f.Profiles[profile]; !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownProfile, profile)
+		}
+		return profile, nil
+	}
+
+	switch len(f.Profiles) {
+	case 0:
+		return "", nil
+	case 1:
+		for name := range f.Profiles {
+			return name, nil
+		}
+	}
+
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return "", fmt.Errorf("%w (available: %s)", ErrNoProfileSelected, strings.Join(names, ", "))
+}
+
+// toConfig converts a profile to a Config
+func (p Profile) toConfig(now time.Time) (*Config, error) {
+	token := p.Token
+	if p.TokenEnv != "" {
+		token = os.Getenv(p.TokenEnv)
+	}
+
+	activeSince, err := ParseActiveSince(p.Filter.ActiveSince, now)
+	if err != nil {
+		return nil, err
+	}
+
+	minAccessLevel, err := ParseAccessLevel(p.Filter.MinAccessLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
+		GitLabHost:  p.Host,
+		GitLabUser:  p.User,
+		GitLabToken: token,
+
+		TokenCommand:      p.TokenCommand,
+		TokenFile:         expandHome(p.TokenFile),
+		CredentialSources: p.CredentialSources,
+
+		Selection: Selection{
+			Groups:   p.groups(),
+			Users:    p.Users,
+			Owned:    p.Owned,
+			Starred:  p.Starred,
+			Personal: p.Personal,
+		},
+		TargetDir: expandHome(p.TargetDir),
+		Jobs:      p.Jobs,
+		Protocol:  p.Protocol,
+		SSHKey:    expandHome(p.SSHKey),
+
+		RetryBackoff: p.RetryBackoff,
+
+		Branch:      p.Branch,
+		CloneFilter: p.CloneFilter,
+
+		OnConflict: p.OnConflict,
+
+		Filter: Filter{
+			Visibility:     p.Filter.Visibility,
+			Archived:       p.Filter.Archived,
+			Topics:         p.Filter.Topics,
+			Include:        p.Filter.Include,
+			Exclude:        p.Filter.Exclude,
+			ExcludeGroups:  p.Filter.ExcludeGroups,
+			ActiveSince:    activeSince,
+			MinAccessLevel: minAccessLevel,
+		},
+	}
+
+	// Settings present in the profile are set even if to their zero value
+	cfg.Mirror = explicit(&cfg.Set, FieldMirror, p.Mirror)
+	cfg.Wikis = explicit(&cfg.Set, FieldWikis, p.Wikis)
+	cfg.LFS = explicit(&cfg.Set, FieldLFS, p.LFS)
+	cfg.LFSFetchOnly = explicit(&cfg.Set, FieldLFSFetchOnly, p.LFSFetchOnly)
+	cfg.RecurseSubmodules = explicit(&cfg.Set, FieldRecurseSubmodules, p.RecurseSubmodules)
+	cfg.SingleBranch = explicit(&cfg.Set, FieldSingleBranch, p.SingleBranch)
+	cfg.Depth = explicit(&cfg.Set, FieldDepth, p.Depth)
+	cfg.Retries = max(explicit(&cfg.Set, FieldRetries, p.Retries), 0)
+
+	return cfg, nil
+}
+
+// explicit returns the value of an optional profile setting and marks field
+// in set if the setting is present
+func explicit[T any](set *Field, field Field, value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+
+	*set |= field
+	return *value
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && rest[0] != '/') {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, rest)
+}
+
+// groups returns the groups of the profile, set by either group or groups
+func (p *Profile) groups() []string {
+	if p.Group == "" {
+		return p.Groups
+	}
+
+	return append([]string{p.Group}, p.Groups...)
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFileExplicitZero(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "profiles:\n  work:\n    host: gitlab.example.com\n    retries: 0\n    lfs: false\n    mirror: true\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path, "", true)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	want := FieldRetries | FieldLFS | FieldMirror
+	if cfg.Set != want {
+		t.Errorf("Set = %b, want %b", cfg.Set, want)
+	}
+
+	cfg.Merge(Defaults())
+	if cfg.Retries != 0 || cfg.LFS || !cfg.Mirror {
+		t.Errorf("Retries, LFS, Mirror = %d, %v, %v, want 0, false, true", cfg.Retries, cfg.LFS, cfg.Mirror)
+	}
+}
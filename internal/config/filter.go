@@ -0,0 +1,182 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Archived filter modes
+const (
+	ArchivedInclude = "include"
+	ArchivedExclude = "exclude"
+	ArchivedOnly    = "only"
+)
+
+// accessLevels maps GitLab role names to access level values
+var accessLevels = map[string]int{
+	"guest":      10,
+	"planner":    15,
+	"reporter":   20,
+	"developer":  30,
+	"maintainer": 40,
+	"owner":      50,
+}
+
+// Filter holds project selection criteria
+type Filter struct {
+	// Visibility is one of public, internal or private; empty means any
+	Visibility string
+	// Archived is one of ArchivedInclude, ArchivedExclude or ArchivedOnly
+	Archived string
+	// Topics must all be set on a project
+	Topics []string
+	// Include are path globs, at least one of which must match PathWithNamespace
+	Include []string
+	// Exclude are path globs, none of which may match PathWithNamespace
+	Exclude []string
+	// ExcludeGroups are namespace globs, none of which may match a namespace
+	// of PathWithNamespace
+	ExcludeGroups []string
+	// ActiveSince is the earliest last activity time; zero means any
+	ActiveSince time.Time
+	// MinAccessLevel is the minimal access level of the user; zero means any
+	MinAccessLevel int
+}
+
+// Validate checks that all filter values are valid
+func (f *Filter) Validate() error {
+	switch f.Visibility {
+	case "", "public", "internal", "private":
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidVisibility, f.Visibility)
+	}
+
+	switch f.Archived {
+	case "", ArchivedInclude, ArchivedExclude, ArchivedOnly:
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidArchived, f.Archived)
+	}
+
+	for _, pattern := range slices.Concat(f.Include, f.Exclude, f.ExcludeGroups) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidPattern, pattern)
+		}
+	}
+
+	return nil
+}
+
+// Merge merges non-empty values from other into f
+func (f *Filter) Merge(other *Filter) {
+	if f.Visibility == "" && other.Visibility != "" {
+		f.Visibility = other.Visibility
+	}
+
+	if f.Archived == "" && other.Archived != "" {
+		f.Archived = other.Archived
+	}
+
+	if len(f.Topics) == 0 && len(other.Topics) > 0 {
+		f.Topics = other.Topics
+	}
+
+	if len(f.Include) == 0 && len(other.Include) > 0 {
+		f.Include = other.Include
+	}
+
+	if len(f.Exclude) == 0 && len(other.Exclude) > 0 {
+		f.Exclude = other.Exclude
+	}
+
+	if len(f.ExcludeGroups) == 0 && len(other.ExcludeGroups) > 0 {
+		f.ExcludeGroups = other.ExcludeGroups
+	}
+
+	if f.ActiveSince.IsZero() && !other.ActiveSince.IsZero() {
+		f.ActiveSince = other.ActiveSince
+	}
+
+	if f.MinAccessLevel == 0 && other.MinAccessLevel != 0 {
+		f.MinAccessLevel = other.MinAccessLevel
+	}
+}
+
+// MatchPath reports whether a project path passes the include, exclude and
+// exclude group globs. A glob matches a path if it matches the path itself or
+// any of its parent namespaces, so "backend" and "backend/*" both select
+// everything under backend/. Exclude group globs only match the namespaces.
+func (f *Filter) MatchPath(projectPath string) bool {
+	if len(f.Include) > 0 && !matchAny(f.Include, projectPath) {
+		return false
+	}
+
+	if matchAny(f.ExcludeGroups, path.Dir(projectPath)) {
+		return false
+	}
+
+	return !matchAny(f.Exclude, projectPath)
+}
+
+func matchAny(patterns []string, projectPath string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.Trim(pattern, "/")
+
+		for p := projectPath; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ParseActiveSince parses an absolute date (2006-01-02 or RFC 3339) or a
+// duration relative to now (e.g. 720h or 30d)
+func ParseActiveSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidActiveSince, value)
+}
+
+// ParseAccessLevel parses a role name (guest, reporter, developer,
+// maintainer, owner) or a numeric GitLab access level
+func ParseAccessLevel(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	if level, ok := accessLevels[strings.ToLower(value)]; ok {
+		return level, nil
+	}
+
+	if level, err := strconv.Atoi(value); err == nil && level >= 0 {
+		return level, nil
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrInvalidAccessLevel, value)
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFilterMatchPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		path   string
+		want   bool
+	}{
+		{
+			name: "no globs",
+			path: "backend/api",
+			want: true,
+		},
+		{
+			name:   "include namespace",
+			filter: Filter{Include: []string{"backend"}},
+			path:   "backend/api",
+			want:   true,
+		},
+		{
+			name:   "include namespace wildcard",
+			filter: Filter{Include: []string{"backend/*"}},
+			path:   "backend/team/api",
+			want:   true,
+		},
+		{
+			name:   "include with slashes",
+			filter: Filter{Include: []string{"/backend/"}},
+			path:   "backend/api",
+			want:   true,
+		},
+		{
+			name:   "include other namespace",
+			filter: Filter{Include: []string{"frontend"}},
+			path:   "backend/api",
+			want:   false,
+		},
+		{
+			name:   "include prefix is not a namespace",
+			filter: Filter{Include: []string{"back"}},
+			path:   "backend/api",
+			want:   false,
+		},
+		{
+			name:   "exclude project",
+			filter: Filter{Exclude: []string{"*/legacy-*"}},
+			path:   "backend/legacy-api",
+			want:   false,
+		},
+		{
+			name:   "exclude wins over include",
+			filter: Filter{Include: []string{"backend"}, Exclude: []string{"backend/api"}},
+			path:   "backend/api",
+			want:   false,
+		},
+		{
+			name:   "exclude group",
+			filter: Filter{ExcludeGroups: []string{"backend/archive"}},
+			path:   "backend/archive/old",
+			want:   false,
+		},
+		{
+			name:   "exclude group matches subgroups",
+			filter: Filter{ExcludeGroups: []string{"backend"}},
+			path:   "backend/team/api",
+			want:   false,
+		},
+		{
+			name:   "exclude group doesn't match the project",
+			filter: Filter{ExcludeGroups: []string{"backend/api"}},
+			path:   "backend/api",
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.MatchPath(tt.path); got != tt.want {
+				t.Errorf("MatchPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseActiveSince(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "", want: time.Time{}},
+		{value: "2024-01-02", want: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{value: "2024-01-02T10:30:00Z", want: time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC)},
+		{value: "2024-01-02T10:30:00+02:00", want: time.Date(2024, 1, 2, 8, 30, 0, 0, time.UTC)},
+		{value: "30d", want: time.Date(2024, 2, 14, 12, 0, 0, 0, time.UTC)},
+		{value: "0d", want: now},
+		{value: "36h", want: time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{value: "-5d", wantErr: true},
+		{value: "-1h", wantErr: true},
+		{value: "5 days", wantErr: true},
+		{value: "2024-13-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseActiveSince(tt.value, now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidActiveSince) {
+					t.Errorf("ParseActiveSince(%q) error = %v, want %v", tt.value, err, ErrInvalidActiveSince)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseActiveSince(%q) error = %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseActiveSince(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
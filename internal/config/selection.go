@@ -0,0 +1,21 @@
+package config
+
+// Selection holds the sources projects are selected from. Projects of all
+// sources are combined; an empty selection selects all accessible projects.
+type Selection struct {
+	// Groups select projects of these groups and their subgroups
+	Groups []string
+	// Users select the personal projects of these users
+	Users []string
+	// Owned selects projects owned by the current user
+	Owned bool
+	// Starred selects projects starred by the current user
+	Starred bool
+	// Personal selects the projects in the personal namespace of the current user
+	Personal bool
+}
+
+// IsEmpty reports whether no source is selected
+func (s *Selection) IsEmpty() bool {
+	return len(s.Groups) == 0 && len(s.Users) == 0 && !s.Owned && !s.Starred && !s.Personal
+}
@@ -0,0 +1,51 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	config "github.com/adzpm/glone/internal/config"
+)
+
+// CommandProvider reads the token from the output of a shell command,
+// e.g. "pass show gitlab" or "secret-tool lookup service gitlab"
+type CommandProvider struct {
+	command string
+}
+
+// NewCommandProvider creates a provider running command with sh -c
+func NewCommandProvider(command string) *CommandProvider {
+	return &CommandProvider{command: command}
+}
+
+// Name returns the source name of the provider
+func (p *CommandProvider) Name() string {
+	return SourceTokenCommand
+}
+
+// Credentials runs the command and uses the first line of its output as the token
+func (p *CommandProvider) Credentials(ctx context.Context, _ string) (*config.Config, error) {
+	if p.command == "" {
+		return nil, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.command)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	token, _, _ := strings.Cut(string(out), "\n")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("token command returned no token")
+	}
+
+	return &config.Config{GitLabToken: token}, nil
+}
@@ -0,0 +1,59 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	config "github.com/adzpm/glone/internal/config"
+)
+
+// FileProvider reads the token from a file that must not be accessible
+// by group or others
+type FileProvider struct {
+	path string
+}
+
+// NewFileProvider creates a provider reading the token from path
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// Name returns the source name of the provider
+func (p *FileProvider) Name() string {
+	return SourceTokenFile
+}
+
+// Credentials checks the file permissions and reads the token from the first line
+func (p *FileProvider) Credentials(_ context.Context, _ string) (*config.Config, error) {
+	if p.path == "" {
+		return nil, nil
+	}
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("token file %s is not a regular file", p.path)
+	}
+
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		return nil, fmt.Errorf("token file %s has insecure permissions %#o, must not be accessible by group or others (chmod 600)", p.path, perm)
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	token, _, _ := strings.Cut(string(data), "\n")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("token file %s is empty", p.path)
+	}
+
+	return &config.Config{GitLabToken: token}, nil
+}
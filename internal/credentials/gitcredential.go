@@ -0,0 +1,76 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	config "github.com/adzpm/glone/internal/config"
+)
+
+// GitCredentialProvider asks the configured git credential helpers
+// for credentials using "git credential fill"
+type GitCredentialProvider struct{}
+
+// NewGitCredentialProvider creates a git credential helper provider
+func NewGitCredentialProvider() *GitCredentialProvider {
+	return &GitCredentialProvider{}
+}
+
+// Name returns the source name of the provider
+func (p *GitCredentialProvider) Name() string {
+	return SourceGitCredential
+}
+
+// Credentials runs "git credential fill" for the host. Interactive prompts are disabled.
+func (p *GitCredentialProvider) Credentials(ctx context.Context, host string) (*config.Config, error) {
+	// Credential helpers are keyed by host
+	if host == "" {
+		return nil, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+
+	if err := cmd.Run(); err != nil {
+		// No helper knows the host, which is not an error for the chain
+		if _, ok := err.(*exec.ExitError); ok {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("git credential fill failed: %w", err)
+	}
+
+	var user, password string
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "username":
+			user = value
+		case "password":
+			password = value
+		}
+	}
+
+	if password == "" {
+		return nil, nil
+	}
+
+	return &config.Config{
+		GitLabHost:  host,
+		GitLabUser:  user,
+		GitLabToken: password,
+	}, nil
+}
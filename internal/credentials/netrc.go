@@ -0,0 +1,46 @@
+package credentials
+
+import (
+	"context"
+
+	config "github.com/adzpm/glone/internal/config"
+	logger "github.com/adzpm/glone/internal/logger"
+	netrc "github.com/adzpm/glone/internal/netrc"
+)
+
+// NetrcProvider loads credentials from a .netrc file
+type NetrcProvider struct {
+	path   string
+	logger logger.Logger
+}
+
+// NewNetrcProvider creates a provider reading path, or ~/.netrc if path is empty
+func NewNetrcProvider(path string, lgr logger.Logger) *NetrcProvider {
+	return &NetrcProvider{
+		path:   path,
+		logger: lgr,
+	}
+}
+
+// Name returns the source name of the provider
+func (p *NetrcProvider) Name() string {
+	return SourceNetrc
+}
+
+// Credentials loads the credentials for host from .netrc
+func (p *NetrcProvider) Credentials(_ context.Context, host string) (*config.Config, error) {
+	opts := []netrc.LoaderOption{
+		netrc.WithLogger(p.logger),
+		netrc.WithHost(host),
+	}
+	if p.path != "" {
+		opts = append(opts, netrc.WithNetrcPath(p.path))
+	}
+
+	loader, err := netrc.NewLoader(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.LoadCredentials()
+}
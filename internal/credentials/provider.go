@@ -0,0 +1,97 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	config "github.com/adzpm/glone/internal/config"
+	logger "github.com/adzpm/glone/internal/logger"
+)
+
+// Source names used to configure the provider order
+const (
+	SourceTokenCommand  = "token-command"
+	SourceTokenFile     = "token-file"
+	SourceNetrc         = "netrc"
+	SourceGitCredential = "git-credential"
+)
+
+// DefaultSources is the provider order used when none is configured
+var DefaultSources = []string{SourceTokenCommand, SourceTokenFile, SourceNetrc}
+
+// Provider supplies GitLab credentials
+type Provider interface {
+	// Name returns the source name of the provider
+	Name() string
+	// Credentials returns the credentials known to the provider for host,
+	// which may be empty if unknown. It returns nil if the provider has no
+	// credentials; missing fields are filled by the next providers.
+	Credentials(ctx context.Context, host string) (*config.Config, error)
+}
+
+// Chain queries providers in order until host, user and token are known
+type Chain struct {
+	providers []Provider
+	logger    logger.Logger
+}
+
+// NewChain creates a chain of providers
+func NewChain(lgr logger.Logger, providers ...Provider) *Chain {
+	return &Chain{
+		providers: providers,
+		logger:    lgr,
+	}
+}
+
+// Fill merges credentials from the providers into cfg. Values already set
+// in cfg take precedence.
+func (c *Chain) Fill(ctx context.Context, cfg *config.Config) error {
+	for _, p := range c.providers {
+		if complete(cfg) {
+			return nil
+		}
+
+		creds, err := p.Credentials(ctx, cfg.GitLabHost)
+		if err != nil {
+			return fmt.Errorf("error loading credentials from %s: %w", p.Name(), err)
+		}
+
+		if creds == nil {
+			continue
+		}
+
+		if c.logger != nil {
+			c.logger.Infof("Using credentials from %s", p.Name())
+		}
+		cfg.Merge(creds)
+	}
+
+	return nil
+}
+
+// complete reports whether cfg has all credentials
+func complete(cfg *config.Config) bool {
+	return cfg.GitLabHost != "" && cfg.GitLabUser != "" && cfg.GitLabToken != ""
+}
+
+// ParseSources splits a comma-separated list of source names and validates it
+func ParseSources(value string) ([]string, error) {
+	if value == "" {
+		return DefaultSources, nil
+	}
+
+	var sources []string
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		switch s {
+		case SourceTokenCommand, SourceTokenFile, SourceNetrc, SourceGitCredential:
+			sources = append(sources, s)
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown credential source: %s", s)
+		}
+	}
+
+	return sources, nil
+}
@@ -1,85 +1,208 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strings"
 
 	git "github.com/go-git/go-git/v5"
+	plumbing "github.com/go-git/go-git/v5/plumbing"
+	transport "github.com/go-git/go-git/v5/plumbing/transport"
 )
 
 // Cloner handles git cloning operations
 type Cloner struct {
-	opts *ClonerOptions
+	opts     *ClonerOptions
+	progress io.Writer
+	sshAuth  transport.AuthMethod
 }
 
 // NewCloner creates a new cloner with options
-func NewCloner(opts ...ClonerOption) *Cloner {
+func NewCloner(opts ...ClonerOption) (*Cloner, error) {
 	options := defaultClonerOptions()
 	for _, opt := range opts {
 		opt(options)
 	}
-	return &Cloner{opts: options}
+
+	c := &Cloner{opts: options}
+	if options.ProgressOut != nil {
+		// Shared between all concurrent clones, so writes must be serialized
+		c.progress = &lockedWriter{w: options.ProgressOut}
+	}
+
+	switch options.Protocol {
+	case ProtocolHTTPS:
+	case ProtocolSSH:
+		auth, err := newSSHAuth(options.SSHKeyPath, options.SSHKeyPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		c.sshAuth = auth
+	default:
+		return nil, fmt.Errorf("unsupported protocol: %s", options.Protocol)
+	}
+
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
 }
 
-// CloneProject clones a project to the target directory
-func (c *Cloner) CloneProject(project *Project, targetDir string, token string) (bool, error) {
-	// Use PathWithNamespace to preserve directory structure
-	projectPath := filepath.Join(targetDir, project.PathWithNamespace)
+// CloneProject clones a project to the target directory.
+// It is safe to call concurrently for different projects.
+func (c *Cloner) CloneProject(ctx context.Context, project *Project, targetDir string, token string) (Result, error) {
+	result, err := c.cloneProject(ctx, project, targetDir, token)
+	result, err = c.withSubmodules(ctx, project, targetDir, token, result, err)
+	return c.withLFS(ctx, project, targetDir, token, result, err)
+}
 
-	// Check if directory already exists and if it's a git repository
-	if info, err := os.Stat(projectPath); err == nil {
-		if info.IsDir() {
-			// Check if this is a git repository
-			if _, err := git.PlainOpen(projectPath); err == nil {
-				if c.opts.Logger != nil {
-					c.opts.Logger.Warnf("Project %s already exists in %s, skipping", project.Name, projectPath)
-				}
-				return true, nil // true means the project was skipped
-			}
-			// If directory exists but is not a git repository, remove it
+func (c *Cloner) cloneProject(ctx context.Context, project *Project, targetDir string, token string) (Result, error) {
+	projectPath := c.ProjectPath(targetDir, project)
+
+	// Check if the path already exists and if it's a git repository
+	if _, err := os.Lstat(projectPath); err == nil {
+		if _, err := git.PlainOpen(projectPath); err == nil {
 			if c.opts.Logger != nil {
-				c.opts.Logger.Warnf("Directory %s exists but is not a git repository, removing", projectPath)
+				c.opts.Logger.Warnf("Project %s already exists in %s, skipping", project.Name, projectPath)
 			}
-			os.RemoveAll(projectPath)
+			return ResultSkipped, nil
 		}
-	}
 
-	// Form URL with token for cloning
-	cloneURL := project.HTTPURLToRepo
-	if token != "" && strings.HasPrefix(cloneURL, "http") {
-		// Add token to URL for authentication
-		if strings.HasPrefix(cloneURL, "https://") {
-			cloneURL = strings.Replace(cloneURL, "https://", fmt.Sprintf("https://oauth2:%s@", token), 1)
-		} else if strings.HasPrefix(cloneURL, "http://") {
-			cloneURL = strings.Replace(cloneURL, "http://", fmt.Sprintf("http://oauth2:%s@", token), 1)
+		// The path is not a git repository, apply the conflict policy
+		result, err := c.resolveConflict(project, projectPath, targetDir)
+		if err != nil || result != "" {
+			return result, err
 		}
+
+		result, err = c.clone(ctx, project, projectPath, token)
+		if err != nil {
+			return "", err
+		}
+
+		if c.opts.OnConflict == ConflictBackup {
+			return ResultBackedUp, nil
+		}
+		return ResultReplaced, nil
+	}
+
+	return c.clone(ctx, project, projectPath, token)
+}
+
+// ProjectPath returns the local path of a project. PathWithNamespace is used
+// to preserve the directory structure; mirrors get a ".git" suffix.
+func (c *Cloner) ProjectPath(targetDir string, project *Project) string {
+	projectPath := filepath.Join(targetDir, project.PathWithNamespace)
+	if c.opts.Mirror {
+		projectPath += ".git"
+	}
+
+	return projectPath
+}
+
+// branchRef returns the reference of the branch to check out,
+// or an empty name for the remote HEAD
+func (c *Cloner) branchRef(project *Project) plumbing.ReferenceName {
+	branch := c.branchName(project)
+	if branch == "" {
+		return ""
 	}
 
+	return plumbing.NewBranchReferenceName(branch)
+}
+
+// branchName returns the name of the branch to check out,
+// or an empty string for the remote HEAD
+func (c *Cloner) branchName(project *Project) string {
+	// Wikis have their own branches, the branch option only applies to projects
+	if c.opts.Branch == "" || c.opts.Branch == BranchDefault || project.Wiki {
+		return project.DefaultBranch
+	}
+
+	return c.opts.Branch
+}
+
+// clone clones a project into projectPath, which must not exist.
+// Transient failures are retried according to the retry policy.
+func (c *Cloner) clone(ctx context.Context, project *Project, projectPath string, token string) (Result, error) {
 	// Create parent directories if they don't exist
 	parentDir := filepath.Dir(projectPath)
 	if err := os.MkdirAll(parentDir, 0755); err != nil {
-		return false, fmt.Errorf("failed to create parent directory %s: %w", parentDir, err)
+		return "", fmt.Errorf("failed to create parent directory %s: %w", parentDir, err)
 	}
 
 	// Clone repository (git.PlainClone creates the final directory itself)
 	if c.opts.Logger != nil {
 		c.opts.Logger.Infof("Cloning %s to %s", project.Name, projectPath)
 	}
-	_, err := git.PlainClone(projectPath, false, &git.CloneOptions{
-		URL:      cloneURL,
-		Progress: c.opts.ProgressOut,
-	})
 
+	err := c.withRetry(ctx, project, func() error {
+		var err error
+
+		// go-git doesn't support partial clones, use the git binary for them
+		if c.opts.Filter != "" {
+			err = c.cloneWithGit(ctx, project, projectPath, token)
+		} else {
+			err = c.plainClone(ctx, project, projectPath, token)
+		}
+
+		if err != nil {
+			// Remove the partially created directory, also before retrying
+			os.RemoveAll(projectPath)
+		}
+		return err
+	})
+	if project.Wiki && (errors.Is(err, transport.ErrEmptyRemoteRepository) || errors.Is(err, transport.ErrRepositoryNotFound)) {
+		if c.opts.Logger != nil {
+			c.opts.Logger.Infof("%s is empty, skipping", project.Name)
+		}
+		return ResultEmpty, nil
+	}
 	if err != nil {
-		// Remove directory on error
-		os.RemoveAll(projectPath)
-		return false, fmt.Errorf("error cloning %s: %w", project.Name, err)
+		return "", fmt.Errorf("error cloning %s: %w", project.Name, err)
 	}
 
 	if c.opts.Logger != nil {
 		c.opts.Logger.Infof("Successfully cloned: %s", project.Name)
 	}
-	return false, nil // false means the project was successfully cloned
+	return ResultCloned, nil
+}
+
+// plainClone clones a project using go-git
+func (c *Cloner) plainClone(ctx context.Context, project *Project, projectPath string, token string) error {
+	// Form URL and authentication for cloning
+	cloneURL, auth := c.remote(project, token)
+
+	cloneOpts := &git.CloneOptions{
+		URL:           cloneURL,
+		Auth:          auth,
+		Mirror:        c.opts.Mirror,
+		Depth:         c.opts.Depth,
+		SingleBranch:  c.opts.SingleBranch,
+		ReferenceName: c.branchRef(project),
+	}
+	progress := c.progressWriter(project)
+	if progress != nil {
+		cloneOpts.Progress = progress
+	}
+
+	_, err := git.PlainCloneContext(ctx, projectPath, false, cloneOpts)
+	if progress != nil {
+		progress.Flush()
+	}
+
+	return err
+}
+
+// progressWriter returns a per-project writer for git progress output,
+// or nil if progress output is disabled
+func (c *Cloner) progressWriter(project *Project) *prefixWriter {
+	if c.progress == nil {
+		return nil
+	}
+
+	return newPrefixWriter(c.progress, project.PathWithNamespace)
 }
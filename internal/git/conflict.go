@@ -0,0 +1,89 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Policies for existing paths that are not git repositories
+const (
+	// ConflictSkip leaves the path untouched and doesn't clone the project
+	ConflictSkip = "skip"
+	// ConflictBackup moves the path to the backup directory and clones the project
+	ConflictBackup = "backup"
+	// ConflictRemove deletes the path and clones the project
+	ConflictRemove = "remove"
+	// ConflictFail reports an error for the project
+	ConflictFail = "fail"
+)
+
+// DataDir is the directory in the target directory where glone keeps its own data
+const DataDir = ".glone"
+
+// BackupDir is the directory, relative to the target directory, that
+// conflicting paths are moved to with the backup policy
+const BackupDir = DataDir + "/backups"
+
+// resolveConflict applies the conflict policy to projectPath, which exists
+// but is not a git repository. It returns a non-empty result if the project
+// must not be cloned.
+func (c *Cloner) resolveConflict(project *Project, projectPath string, targetDir string) (Result, error) {
+	switch c.opts.OnConflict {
+	case ConflictBackup:
+		backupPath, err := moveAside(projectPath, targetDir, BackupDir)
+		if err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", projectPath, err)
+		}
+
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("%s exists but is not a git repository, moved to %s", projectPath, backupPath)
+		}
+		return "", nil
+	case ConflictRemove:
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("%s exists but is not a git repository, removing", projectPath)
+		}
+
+		if err := os.RemoveAll(projectPath); err != nil {
+			return "", fmt.Errorf("failed to remove %s: %w", projectPath, err)
+		}
+		return "", nil
+	case ConflictFail:
+		return "", fmt.Errorf("%s exists but is not a git repository", projectPath)
+	default:
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("%s exists but is not a git repository, skipping %s", projectPath, project.Name)
+		}
+		return ResultConflict, nil
+	}
+}
+
+// moveAside moves path, which is inside targetDir, to a timestamped location
+// in dir relative to targetDir and returns the new location
+func moveAside(path string, targetDir string, dir string) (string, error) {
+	rel, err := filepath.Rel(targetDir, path)
+	if err != nil {
+		return "", err
+	}
+
+	base := filepath.Join(targetDir, dir, time.Now().Format("20060102-150405"), rel)
+	newPath := base
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(newPath); os.IsNotExist(err) {
+			break
+		}
+		newPath = fmt.Sprintf("%s.%d", base, i)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(path, newPath); err != nil {
+		return "", err
+	}
+
+	return newPath, nil
+}
@@ -0,0 +1,242 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	plumbing "github.com/go-git/go-git/v5/plumbing"
+)
+
+// runGit runs the git binary in dir. It is used for features go-git doesn't
+// support, such as partial clones. Credentials are passed through the
+// environment, so they are neither visible in the process list nor persisted.
+func (c *Cloner) runGit(ctx context.Context, dir string, project *Project, token string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), c.gitEnv(token)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// Progress is shown only for commands transferring data
+	var progress *prefixWriter
+	if project != nil {
+		progress = c.progressWriter(project)
+	}
+	if progress != nil {
+		cmd.Stderr = io.MultiWriter(&stderr, progress)
+	}
+
+	err := cmd.Run()
+	if progress != nil {
+		progress.Flush()
+	}
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(lastLine(stderr.String())))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// gitEnv returns environment variables that configure authentication
+// and disable interactive prompts
+func (c *Cloner) gitEnv(token string) []string {
+	// LFS objects are downloaded by glone itself, see WithLFS
+	env := []string{"GIT_TERMINAL_PROMPT=0", "GIT_LFS_SKIP_SMUDGE=1"}
+
+	if c.opts.Protocol == ProtocolSSH {
+		sshCommand := "ssh -o BatchMode=yes"
+		if c.opts.SSHKeyPath != "" {
+			sshCommand += " -o IdentitiesOnly=yes -i " + strconv.Quote(c.opts.SSHKeyPath)
+		}
+		return append(env, "GIT_SSH_COMMAND="+sshCommand)
+	}
+
+	if token != "" {
+		basic := base64.StdEncoding.EncodeToString([]byte(tokenUser + ":" + token))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+basic,
+		)
+	}
+
+	return env
+}
+
+// cloneWithGit makes a partial clone using the git binary
+func (c *Cloner) cloneWithGit(ctx context.Context, project *Project, projectPath string, token string) error {
+	cloneURL, _ := c.remote(project, "")
+
+	args := []string{"clone", "--progress", "--filter=" + c.opts.Filter}
+	if c.opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(c.opts.Depth))
+	}
+	if c.opts.SingleBranch {
+		args = append(args, "--single-branch")
+	} else if c.opts.Depth > 0 {
+		// git implies --single-branch with --depth, go-git doesn't
+		args = append(args, "--no-single-branch")
+	}
+	if branch := c.branchName(project); branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	args = append(args, "--", cloneURL, projectPath)
+
+	_, err := c.runGit(ctx, "", project, token, args...)
+	return err
+}
+
+// syncWithGit fetches and fast-forwards a partial clone using the git binary,
+// since go-git can't fetch the objects missing from it
+func (c *Cloner) syncWithGit(ctx context.Context, repo *git.Repository, project *Project, projectPath string, token string) (Result, error) {
+	status, err := c.runGit(ctx, projectPath, nil, "", "status", "--porcelain=v2", "-z")
+	if err != nil {
+		return "", fmt.Errorf("error getting status of %s: %w", project.Name, err)
+	}
+
+	// Files replaced with their LFS object are modified for git without the
+	// git-lfs extension, like for go-git
+	clean, err := gitStatusClean(repo, projectPath, status)
+	if err != nil {
+		return "", fmt.Errorf("error getting status of %s: %w", project.Name, err)
+	}
+
+	if !clean {
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("Project %s has local changes in %s, skipping", project.Name, projectPath)
+		}
+		return ResultDirty, nil
+	}
+
+	branch, err := c.runGit(ctx, projectPath, nil, "", "symbolic-ref", "-q", "--short", "HEAD")
+	if err != nil {
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("Project %s is in detached HEAD state, skipping", project.Name)
+		}
+		return ResultSkipped, nil
+	}
+
+	if c.opts.Logger != nil {
+		c.opts.Logger.Infof("Fetching %s", project.Name)
+	}
+
+	fetchArgs := []string{"fetch", "--progress"}
+	if c.opts.Unshallow && isShallow(projectPath) {
+		fetchArgs = append(fetchArgs, "--unshallow")
+	}
+	fetchArgs = append(fetchArgs, git.DefaultRemoteName)
+
+	err = c.withRetry(ctx, project, func() error {
+		_, err := c.runGit(ctx, projectPath, project, token, fetchArgs...)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("error fetching %s: %w", project.Name, err)
+	}
+
+	local, err := c.runGit(ctx, projectPath, nil, "", "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("error resolving HEAD of %s: %w", project.Name, err)
+	}
+
+	// Like on the go-git path, a branch without upstream configuration
+	// follows the branch of the same name on origin
+	remote, err := c.runGit(ctx, projectPath, nil, "", "rev-parse", "--verify", "-q", "@{upstream}")
+	if err != nil {
+		remote, err = c.runGit(ctx, projectPath, nil, "", "rev-parse", "--verify", "-q",
+			plumbing.NewRemoteReferenceName(git.DefaultRemoteName, branch).String())
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", fmt.Errorf("error resolving upstream of %s: %w", project.Name, err)
+		}
+
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("Branch %s of %s has no upstream branch, skipping", branch, project.Name)
+		}
+		return ResultNoUpstream, nil
+	}
+
+	if local == remote {
+		return ResultUpToDate, nil
+	}
+
+	// Local branch is ahead of upstream, nothing to fast-forward
+	if _, err := c.runGit(ctx, projectPath, nil, "", "merge-base", "--is-ancestor", remote, local); err == nil {
+		return ResultUpToDate, nil
+	}
+
+	if _, err := c.runGit(ctx, projectPath, nil, "", "merge-base", "--is-ancestor", local, remote); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", fmt.Errorf("error comparing commits of %s: %w", project.Name, err)
+		}
+
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("Branch %s of %s diverged from upstream, skipping", branch, project.Name)
+		}
+		return ResultDiverged, nil
+	}
+
+	if _, err := c.runGit(ctx, projectPath, project, token, "merge", "--ff-only", remote); err != nil {
+		return "", fmt.Errorf("error fast-forwarding %s: %w", project.Name, err)
+	}
+
+	if c.opts.Logger != nil {
+		c.opts.Logger.Infof("Updated %s: %s -> %s", project.Name, local[:8], remote[:8])
+	}
+
+	return ResultUpdated, nil
+}
+
+// isPartialClone reports whether a repository was cloned with a filter
+func isPartialClone(repo *git.Repository) bool {
+	cfg, err := repo.Config()
+	if err != nil {
+		return false
+	}
+
+	if cfg.Raw.Section("extensions").Option("partialclone") != "" {
+		return true
+	}
+
+	for _, remote := range cfg.Raw.Section("remote").Subsections {
+		if remote.Option("promisor") == "true" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isShallow reports whether the repository at path is a shallow clone
+func isShallow(path string) bool {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return false
+	}
+
+	shallows, err := repo.Storer.Shallow()
+	return err == nil && len(shallows) > 0
+}
+
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexAny(s, "\r\n"); i >= 0 {
+		return s[i+1:]
+	}
+
+	return s
+}
@@ -0,0 +1,350 @@
+package git
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	plumbing "github.com/go-git/go-git/v5/plumbing"
+	index "github.com/go-git/go-git/v5/plumbing/format/index"
+	object "github.com/go-git/go-git/v5/plumbing/object"
+
+	lfs "github.com/adzpm/glone/internal/lfs"
+)
+
+// fetchedResults are the results after which the repository holds the
+// commits of the remote, so LFS objects and submodules can be fetched
+var fetchedResults = map[Result]bool{
+	ResultCloned:   true,
+	ResultUpdated:  true,
+	ResultUpToDate: true,
+	ResultBackedUp: true,
+	ResultReplaced: true,
+}
+
+// withLFS downloads the LFS objects of a project after it was cloned or
+// synced successfully, if LFS support is enabled
+func (c *Cloner) withLFS(ctx context.Context, project *Project, targetDir string, token string, result Result, err error) (Result, error) {
+	if !c.opts.LFS || err != nil || project.Wiki || !fetchedResults[result] {
+		return result, err
+	}
+
+	if err := c.pullLFS(ctx, project, c.ProjectPath(targetDir, project), token); err != nil {
+		return "", fmt.Errorf("error fetching LFS objects of %s: %w", project.Name, err)
+	}
+
+	return result, nil
+}
+
+// pullLFS downloads the LFS objects referenced by the checked-out commit, or
+// by all branches of a mirror, to the lfs directory of the repository and
+// replaces the pointer files in the worktree with their contents
+func (c *Cloner) pullLFS(ctx context.Context, project *Project, projectPath string, token string) error {
+	repo, err := git.PlainOpen(projectPath)
+	if err != nil {
+		return err
+	}
+
+	bare := false
+	lfsDir := filepath.Join(projectPath, ".git", "lfs")
+	if _, err := repo.Worktree(); errors.Is(err, git.ErrIsBareRepository) {
+		bare = true
+		lfsDir = filepath.Join(projectPath, "lfs")
+	}
+
+	trees, err := lfsTrees(repo, bare)
+	if err != nil {
+		return err
+	}
+
+	// Pointers of the checked-out tree by path, and of all trees by object ID
+	files := make(map[string]lfs.Pointer)
+	pointers := make(map[string]lfs.Pointer)
+	for i, tree := range trees {
+		treeFiles, err := lfsPointers(tree)
+		if err != nil {
+			return err
+		}
+
+		for path, p := range treeFiles {
+			if i == 0 && !bare {
+				files[path] = p
+			}
+			pointers[p.OID] = p
+		}
+	}
+
+	var missing []lfs.Pointer
+	for _, p := range pointers {
+		if !lfs.HasObject(lfsDir, p) {
+			missing = append(missing, p)
+		}
+	}
+
+	if len(missing) > 0 {
+		if c.opts.Logger != nil {
+			c.opts.Logger.Infof("Fetching %d LFS objects of %s", len(missing), project.Name)
+		}
+
+		client := lfs.NewClient(project.HTTPURLToRepo, token)
+		err := c.withRetry(ctx, project, func() error {
+			return client.Download(ctx, missing, lfsDir)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if bare || c.opts.LFSFetchOnly {
+		return nil
+	}
+
+	for path, p := range files {
+		if err := smudge(filepath.Join(projectPath, filepath.FromSlash(path)), lfsDir, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lfsTrees returns the trees to look for LFS pointers in that have LFS
+// filters set in .gitattributes: the HEAD tree, or the trees of all
+// branches of a bare repository
+func lfsTrees(repo *git.Repository, bare bool) ([]*object.Tree, error) {
+	var hashes []plumbing.Hash
+	if bare {
+		branches, err := repo.Branches()
+		if err != nil {
+			return nil, err
+		}
+		_ = branches.ForEach(func(ref *plumbing.Reference) error {
+			hashes = append(hashes, ref.Hash())
+			return nil
+		})
+	} else {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, head.Hash())
+	}
+
+	var trees []*object.Tree
+	for _, hash := range hashes {
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		tree, err := commit.Tree()
+		if err != nil {
+			return nil, err
+		}
+
+		if usesLFS(tree) {
+			trees = append(trees, tree)
+		}
+	}
+
+	return trees, nil
+}
+
+// usesLFS reports whether a .gitattributes file of the tree sets the LFS filter
+func usesLFS(tree *object.Tree) bool {
+	found := false
+	_ = tree.Files().ForEach(func(f *object.File) error {
+		if filepath.Base(f.Name) != ".gitattributes" {
+			return nil
+		}
+
+		contents, err := f.Contents()
+		if err == nil && strings.Contains(contents, "filter=lfs") {
+			found = true
+			return io.EOF
+		}
+		return nil
+	})
+
+	return found
+}
+
+// lfsPointers returns the pointer files of the tree by path
+func lfsPointers(tree *object.Tree) (map[string]lfs.Pointer, error) {
+	pointers := make(map[string]lfs.Pointer)
+	err := tree.Files().ForEach(func(f *object.File) error {
+		if f.Size > lfs.MaxPointerSize || !f.Mode.IsFile() {
+			return nil
+		}
+
+		contents, err := f.Contents()
+		if err != nil {
+			return err
+		}
+
+		if p, ok := lfs.ParsePointer([]byte(contents)); ok {
+			pointers[f.Name] = p
+		}
+		return nil
+	})
+
+	return pointers, err
+}
+
+// smudge replaces the pointer file at path with the contents of its object.
+// Files that are no longer pointers are left untouched.
+func smudge(path string, lfsDir string, p lfs.Pointer) error {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > lfs.MaxPointerSize {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if current, ok := lfs.ParsePointer(data); !ok || current.OID != p.OID {
+		return nil
+	}
+
+	src, err := os.Open(lfs.ObjectPath(lfsDir, p.OID))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".glone-lfs"
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// worktreeClean reports whether the worktree has no local changes. Files
+// that were replaced with their LFS object are not considered changes.
+func worktreeClean(repo *git.Repository, wt *git.Worktree) (bool, error) {
+	status, err := wt.Status()
+	if err != nil {
+		return false, err
+	}
+
+	if status.IsClean() {
+		return true, nil
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return false, err
+	}
+
+	for path, s := range status {
+		if s.Staging == git.Unmodified && s.Worktree == git.Modified && isSmudged(repo, idx.Entries, wt.Filesystem.Root(), path) {
+			continue
+		}
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// gitStatusClean is worktreeClean for the output of
+// git status --porcelain=v2 -z, which is used for partial clones
+func gitStatusClean(repo *git.Repository, root string, status string) (bool, error) {
+	if status == "" {
+		return true, nil
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range strings.Split(status, "\x00") {
+		if entry == "" {
+			continue
+		}
+
+		// Only ordinary entries modified in the worktree alone may be
+		// smudged: "1 .M <sub> <mH> <mI> <mW> <hH> <hI> <path>"
+		fields := strings.SplitN(entry, " ", 9)
+		if len(fields) == 9 && fields[0] == "1" && fields[1] == ".M" && isSmudged(repo, idx.Entries, root, fields[8]) {
+			continue
+		}
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// isSmudged reports whether the file at path holds the LFS object its
+// committed pointer references
+func isSmudged(repo *git.Repository, entries []*index.Entry, root string, path string) bool {
+	var hash plumbing.Hash
+	for _, e := range entries {
+		if e.Name == path {
+			hash = e.Hash
+			break
+		}
+	}
+	if hash.IsZero() {
+		return false
+	}
+
+	blob, err := repo.BlobObject(hash)
+	if err != nil || blob.Size > lfs.MaxPointerSize {
+		return false
+	}
+
+	r, err := blob.Reader()
+	if err != nil {
+		return false
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return false
+	}
+
+	p, ok := lfs.ParsePointer(data)
+	if !ok {
+		return false
+	}
+
+	f, err := os.Open(filepath.Join(root, filepath.FromSlash(path)))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return false
+	}
+
+	return n == p.Size && hex.EncodeToString(h.Sum(nil)) == p.OID
+}
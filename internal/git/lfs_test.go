@@ -0,0 +1,115 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	object "github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestGitStatusClean(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not found")
+	}
+
+	content := "large file content\n"
+	sum := sha256.Sum256([]byte(content))
+	pointer := fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", hex.EncodeToString(sum[:]), len(content))
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  bool
+	}{
+		{
+			name: "unchanged",
+			want: true,
+		},
+		{
+			name:  "smudged",
+			files: map[string]string{"large.bin": content},
+			want:  true,
+		},
+		{
+			name:  "modified LFS file",
+			files: map[string]string{"large.bin": "other content\n"},
+			want:  false,
+		},
+		{
+			name:  "modified file",
+			files: map[string]string{"README.md": "changed\n"},
+			want:  false,
+		},
+		{
+			name:  "untracked file",
+			files: map[string]string{"new.txt": "new\n"},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			repo := commitFiles(t, dir, map[string]string{"large.bin": pointer, "README.md": "readme\n"})
+
+			for name, data := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cmd := exec.Command("git", "status", "--porcelain=v2", "-z")
+			cmd.Dir = dir
+			status, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("git status: %v", err)
+			}
+
+			got, err := gitStatusClean(repo, dir, string(status))
+			if err != nil {
+				t.Fatalf("gitStatusClean() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("gitStatusClean() = %v, want %v (status %q)", got, tt.want, status)
+			}
+		})
+	}
+}
+
+// commitFiles creates a repository in dir with files committed
+func commitFiles(t *testing.T, dir string, files map[string]string) *git.Repository {
+	t.Helper()
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	if _, err := wt.Commit("initial", &git.CommitOptions{Author: signature}); err != nil {
+		t.Fatal(err)
+	}
+
+	return repo
+}
@@ -0,0 +1,80 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	git "github.com/go-git/go-git/v5"
+	config "github.com/go-git/go-git/v5/config"
+	plumbing "github.com/go-git/go-git/v5/plumbing"
+)
+
+// mirrorRefSpec maps all remote refs (branches, tags, notes, merge request refs) to local refs
+const mirrorRefSpec = config.RefSpec("+refs/*:refs/*")
+
+// syncMirror updates a bare mirror with all refs, removing refs deleted on the remote
+func (c *Cloner) syncMirror(ctx context.Context, repo *git.Repository, project *Project, token string) (Result, error) {
+	if c.opts.Logger != nil {
+		c.opts.Logger.Infof("Fetching mirror %s", project.Name)
+	}
+
+	before, err := refHashes(repo)
+	if err != nil {
+		return "", fmt.Errorf("error reading refs of mirror %s: %w", project.Name, err)
+	}
+
+	fetchURL, auth := c.remote(project, token)
+	fetchOpts := &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RemoteURL:  fetchURL,
+		Auth:       auth,
+		RefSpecs:   []config.RefSpec{mirrorRefSpec},
+		Tags:       git.AllTags,
+		Force:      true,
+		Prune:      true,
+	}
+	err = c.fetch(ctx, repo, project, fetchOpts)
+
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return ResultUpToDate, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error fetching mirror %s: %w", project.Name, err)
+	}
+
+	// Pruning makes go-git report changes even if no ref moved, so compare refs
+	after, err := refHashes(repo)
+	if err != nil {
+		return "", fmt.Errorf("error reading refs of mirror %s: %w", project.Name, err)
+	}
+
+	if maps.Equal(before, after) {
+		return ResultUpToDate, nil
+	}
+
+	if c.opts.Logger != nil {
+		c.opts.Logger.Infof("Updated mirror %s", project.Name)
+	}
+
+	return ResultUpdated, nil
+}
+
+// refHashes returns the hashes of all refs of a repository by ref name
+func refHashes(repo *git.Repository) (map[plumbing.ReferenceName]plumbing.Hash, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[plumbing.ReferenceName]plumbing.Hash)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference {
+			hashes[ref.Name()] = ref.Hash()
+		}
+		return nil
+	})
+
+	return hashes, err
+}
@@ -0,0 +1,59 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	git "github.com/go-git/go-git/v5"
+)
+
+// MoveProject moves the clone of a project that was renamed or transferred
+// on GitLab from the path of its old path with namespace to its current path,
+// and points its origin remote to the current URL
+func (c *Cloner) MoveProject(project *Project, oldPathWithNamespace string, targetDir string) error {
+	oldPath := c.ProjectPath(targetDir, &Project{PathWithNamespace: oldPathWithNamespace})
+	newPath := c.ProjectPath(targetDir, project)
+
+	if _, err := os.Lstat(newPath); err == nil {
+		return fmt.Errorf("can't move %s to %s: destination exists", oldPath, newPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory of %s: %w", newPath, err)
+	}
+
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", oldPath, newPath, err)
+	}
+
+	removeEmptyParents(oldPath, targetDir)
+
+	if c.opts.Logger != nil {
+		c.opts.Logger.Infof("Moved %s to %s", oldPath, newPath)
+	}
+
+	repo, err := git.PlainOpen(newPath)
+	if err != nil {
+		return fmt.Errorf("error opening repository %s: %w", newPath, err)
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return fmt.Errorf("error reading config of %s: %w", newPath, err)
+	}
+
+	remote, ok := cfg.Remotes[git.DefaultRemoteName]
+	if !ok {
+		return nil
+	}
+
+	remoteURL, _ := c.remote(project, "")
+	remote.URLs = []string{remoteURL}
+
+	if err := repo.SetConfig(cfg); err != nil {
+		return fmt.Errorf("error updating remote of %s: %w", newPath, err)
+	}
+
+	return nil
+}
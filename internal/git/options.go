@@ -1,18 +1,37 @@
 package git
 
 import (
+	"fmt"
 	"io"
 	"os"
 
 	logger "github.com/adzpm/glone/internal/logger"
+	retry "github.com/adzpm/glone/internal/retry"
 )
 
 // ClonerOptions holds cloner configuration options
 type ClonerOptions struct {
-	Logger      logger.Logger
-	ProgressOut io.Writer
+	Logger           logger.Logger
+	ProgressOut      io.Writer
+	Protocol         string
+	SSHKeyPath       string
+	SSHKeyPassphrase string
+	Mirror           bool
+	Depth            int
+	SingleBranch     bool
+	Branch           string
+	Filter           string
+	Unshallow        bool
+	Retry            retry.Policy
+	OnConflict       string
+	LFS              bool
+	LFSFetchOnly     bool
+	Submodules       bool
 }
 
+// BranchDefault selects the project's default branch
+const BranchDefault = "default"
+
 // ClonerOption is a function that modifies ClonerOptions
 type ClonerOption func(*ClonerOptions)
 
@@ -30,10 +49,133 @@ func WithProgressOutput(w io.Writer) ClonerOption {
 	}
 }
 
+// WithProtocol sets the clone protocol (ProtocolHTTPS or ProtocolSSH)
+func WithProtocol(protocol string) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.Protocol = protocol
+	}
+}
+
+// WithSSHKey sets the private key file and its passphrase for SSH cloning.
+// If not set, ssh-agent is used.
+func WithSSHKey(path, passphrase string) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.SSHKeyPath = path
+		o.SSHKeyPassphrase = passphrase
+	}
+}
+
+// WithMirror enables bare mirror clones with all refs
+func WithMirror(mirror bool) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.Mirror = mirror
+	}
+}
+
+// WithDepth limits clones to the given number of commits; 0 means full history
+func WithDepth(depth int) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.Depth = depth
+	}
+}
+
+// WithSingleBranch clones only the branch that is checked out
+func WithSingleBranch(single bool) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.SingleBranch = single
+	}
+}
+
+// WithBranch sets the branch to check out; BranchDefault or empty means
+// the project's default branch
+func WithBranch(branch string) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.Branch = branch
+	}
+}
+
+// WithFilter sets a partial clone filter such as "blob:none".
+// Partial clones are made with the git binary, as go-git doesn't support them.
+func WithFilter(filter string) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.Filter = filter
+	}
+}
+
+// WithUnshallow fetches the full history of shallow clones when syncing
+func WithUnshallow(unshallow bool) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.Unshallow = unshallow
+	}
+}
+
+// WithRetryPolicy sets how transient clone and fetch failures are retried
+func WithRetryPolicy(policy retry.Policy) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.Retry = policy
+	}
+}
+
+// WithOnConflict sets what to do with project paths that exist but are not
+// git repositories: ConflictSkip, ConflictBackup, ConflictRemove or ConflictFail
+func WithOnConflict(policy string) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.OnConflict = policy
+	}
+}
+
+// WithLFS downloads Git LFS objects after cloning and syncing and replaces
+// the pointer files in the worktree with their contents. With fetchOnly the
+// objects are only stored in the lfs directory of the repository.
+func WithLFS(enabled bool, fetchOnly bool) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.LFS = enabled
+		o.LFSFetchOnly = fetchOnly
+	}
+}
+
+// WithRecurseSubmodules initializes and updates the submodules of projects
+// after cloning and syncing
+func WithRecurseSubmodules(recurse bool) ClonerOption {
+	return func(o *ClonerOptions) {
+		o.Submodules = recurse
+	}
+}
+
 // defaultClonerOptions returns default cloner options
 func defaultClonerOptions() *ClonerOptions {
 	return &ClonerOptions{
 		Logger:      nil,
 		ProgressOut: os.Stdout,
+		Protocol:    ProtocolHTTPS,
+		Retry:       retry.DefaultPolicy(),
+		OnConflict:  ConflictSkip,
+	}
+}
+
+// validate checks that the options can be combined
+func (o *ClonerOptions) validate() error {
+	if o.Depth < 0 {
+		return fmt.Errorf("depth must not be negative")
+	}
+
+	switch o.OnConflict {
+	case ConflictSkip, ConflictBackup, ConflictRemove, ConflictFail:
+	default:
+		return fmt.Errorf("unsupported conflict policy: %s", o.OnConflict)
 	}
+
+	if o.Mirror && (o.Depth > 0 || o.SingleBranch || o.Filter != "" || (o.Branch != "" && o.Branch != BranchDefault)) {
+		return fmt.Errorf("mirror clones can't be combined with depth, single-branch, branch or filter")
+	}
+
+	if o.Submodules && (o.Mirror || o.Filter != "") {
+		return fmt.Errorf("submodules can't be updated in mirror clones or partial clones")
+	}
+
+	if o.Filter != "" && o.Protocol == ProtocolSSH && o.SSHKeyPassphrase != "" {
+		return fmt.Errorf("filter uses the git binary, which doesn't support SSH key passphrases; use ssh-agent")
+	}
+
+	return nil
 }
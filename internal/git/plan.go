@@ -0,0 +1,98 @@
+package git
+
+import (
+	"os"
+
+	git "github.com/go-git/go-git/v5"
+)
+
+// Action describes what a run would do with a project
+type Action string
+
+const (
+	// ActionClone means the project would be cloned
+	ActionClone Action = "clone"
+	// ActionSkip means the project would be left untouched
+	ActionSkip Action = "skip"
+	// ActionUpdate means the clone would be fetched and fast-forwarded if possible
+	ActionUpdate Action = "update"
+	// ActionRemove means the directory at the project path would be removed
+	// and the project cloned into it
+	ActionRemove Action = "remove"
+	// ActionBackup means the directory at the project path would be moved to
+	// the backup directory and the project cloned into it
+	ActionBackup Action = "backup"
+	// ActionMove means the clone of a renamed or transferred project would be
+	// moved to the project's current path
+	ActionMove Action = "move"
+	// ActionFail means the project would fail because its path is taken
+	ActionFail Action = "fail"
+)
+
+// Step is the planned action for a single project
+type Step struct {
+	Project string `json:"project"`
+	Path    string `json:"path"`
+	Action  Action `json:"action"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Plan returns what CloneProject, or SyncProject if update is set, would do
+// with the project. It only inspects the local clone and doesn't access
+// the network or modify the filesystem.
+func (c *Cloner) Plan(project *Project, targetDir string, update bool) Step {
+	projectPath := c.ProjectPath(targetDir, project)
+	step := Step{
+		Project: project.PathWithNamespace,
+		Path:    projectPath,
+	}
+
+	if _, err := os.Lstat(projectPath); err != nil {
+		step.Action = ActionClone
+		return step
+	}
+
+	repo, err := git.PlainOpen(projectPath)
+	if err != nil {
+		step.Reason = "path exists but is not a git repository"
+		switch c.opts.OnConflict {
+		case ConflictBackup:
+			step.Action = ActionBackup
+		case ConflictRemove:
+			step.Action = ActionRemove
+		case ConflictFail:
+			step.Action = ActionFail
+		default:
+			step.Action = ActionSkip
+		}
+		return step
+	}
+
+	if !update {
+		step.Action = ActionSkip
+		step.Reason = "already cloned"
+		return step
+	}
+
+	if c.opts.Mirror {
+		step.Action = ActionUpdate
+		return step
+	}
+
+	if wt, err := repo.Worktree(); err == nil {
+		if clean, err := worktreeClean(repo, wt); err == nil && !clean {
+			step.Action = ActionSkip
+			step.Reason = "worktree has local changes"
+			return step
+		}
+	}
+
+	if head, err := repo.Head(); err == nil && !head.Name().IsBranch() {
+		step.Action = ActionSkip
+		step.Reason = "detached HEAD"
+		return step
+	}
+
+	step.Action = ActionUpdate
+	return step
+}
@@ -0,0 +1,130 @@
+package git
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// lockedWriter serializes writes to the underlying writer so that
+// concurrent clones can share a single progress output
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.w.Write(p)
+}
+
+// prefixWriter buffers the progress of a single project and writes it to
+// the shared output line by line, each line prefixed with the project path.
+// In-place updates are throttled: only the first and the last update of each
+// phase, such as "Receiving objects", are written.
+type prefixWriter struct {
+	out    io.Writer
+	prefix []byte
+	buf    []byte
+
+	// phase is the phase of the last line written
+	phase []byte
+	// pending is the latest in-place update not written yet
+	pending []byte
+}
+
+func newPrefixWriter(out io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{
+		out:    out,
+		prefix: []byte("[" + prefix + "] "),
+	}
+}
+
+// Write buffers p and flushes every complete line; go-git terminates
+// in-place progress updates with '\r' and the final line of a phase with '\n'
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+
+	for {
+		i := bytes.IndexAny(p.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+
+		line := p.buf[:i]
+		final := p.buf[i] == '\n'
+		p.buf = p.buf[i+1:]
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		if err := p.progress(line, final); err != nil {
+			return len(b), err
+		}
+	}
+
+	return len(b), nil
+}
+
+// progress writes a line if it is the final line of a phase or the first
+// update of a new one, and keeps it as pending otherwise
+func (p *prefixWriter) progress(line []byte, final bool) error {
+	phase := progressPhase(line)
+	changed := !bytes.Equal(phase, p.phase)
+
+	// Show where the previous phase stopped if it never finished
+	if changed && p.pending != nil {
+		if err := p.writeLine(p.pending); err != nil {
+			return err
+		}
+	}
+	p.pending = nil
+
+	if !final && !changed {
+		p.pending = append(p.pending, line...)
+		return nil
+	}
+
+	p.phase = append(p.phase[:0], phase...)
+	return p.writeLine(line)
+}
+
+// progressPhase returns the part of a progress line before the counters
+func progressPhase(line []byte) []byte {
+	if i := bytes.IndexByte(line, ':'); i >= 0 {
+		return line[:i]
+	}
+
+	return line
+}
+
+// Flush writes the pending update and any buffered incomplete line
+func (p *prefixWriter) Flush() error {
+	line := p.buf
+	p.buf = nil
+
+	if len(bytes.TrimSpace(line)) != 0 {
+		return p.progress(line, true)
+	}
+
+	pending := p.pending
+	p.pending = nil
+	if pending == nil {
+		return nil
+	}
+
+	return p.writeLine(pending)
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	msg := make([]byte, 0, len(p.prefix)+len(line)+1)
+	msg = append(msg, p.prefix...)
+	msg = append(msg, line...)
+	msg = append(msg, '\n')
+
+	_, err := p.out.Write(msg)
+	return err
+}
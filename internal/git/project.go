@@ -1,11 +1,23 @@
 package git
 
+import "time"
+
 // Project represents a project that can be cloned
 type Project struct {
+	// ID is the GitLab project ID
+	ID int
 	// Name is the project name
 	Name string
 	// PathWithNamespace is the full path including namespace
 	PathWithNamespace string
 	// HTTPURLToRepo is the HTTP URL for cloning the repository
 	HTTPURLToRepo string
+	// SSHURLToRepo is the SSH URL for cloning the repository
+	SSHURLToRepo string
+	// DefaultBranch is the name of the default branch
+	DefaultBranch string
+	// LastActivityAt is the time of the last activity in the project, if known
+	LastActivityAt *time.Time
+	// Wiki marks the wiki repository of a project, which may be empty
+	Wiki bool
 }
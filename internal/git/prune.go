@@ -0,0 +1,124 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	git "github.com/go-git/go-git/v5"
+	plumbing "github.com/go-git/go-git/v5/plumbing"
+	object "github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ArchiveDir is the directory, relative to the target directory, that
+// pruned repositories are moved to when they are archived
+const ArchiveDir = DataDir + "/archive"
+
+// UnpushedBranches returns the local branches of the repository at path
+// with commits that are not contained in any remote-tracking branch.
+// Bare repositories (mirrors) have no local work and never report any.
+func UnpushedBranches(path string) ([]string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening repository %s: %w", path, err)
+	}
+
+	if _, err := repo.Worktree(); err == git.ErrIsBareRepository {
+		return nil, nil
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("error reading references of %s: %w", path, err)
+	}
+
+	var branches, remotes []*plumbing.Reference
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		switch {
+		case ref.Type() != plumbing.HashReference:
+		case ref.Name().IsBranch():
+			branches = append(branches, ref)
+		case ref.Name().IsRemote():
+			remotes = append(remotes, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading references of %s: %w", path, err)
+	}
+
+	var remoteCommits []*object.Commit
+	for _, ref := range remotes {
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			continue
+		}
+		remoteCommits = append(remoteCommits, commit)
+	}
+
+	var unpushed []string
+	for _, branch := range branches {
+		commit, err := repo.CommitObject(branch.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("error reading branch %s of %s: %w", branch.Name().Short(), path, err)
+		}
+
+		if !containedIn(commit, remoteCommits) {
+			unpushed = append(unpushed, branch.Name().Short())
+		}
+	}
+
+	return unpushed, nil
+}
+
+// containedIn reports whether commit is one of commits or an ancestor of one
+func containedIn(commit *object.Commit, commits []*object.Commit) bool {
+	for _, other := range commits {
+		if other.Hash == commit.Hash {
+			return true
+		}
+
+		if ok, err := commit.IsAncestor(other); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ArchiveRepository moves the repository at path, which is inside targetDir,
+// to a timestamped location in ArchiveDir and returns the new location
+func ArchiveRepository(path string, targetDir string) (string, error) {
+	archivePath, err := moveAside(path, targetDir, ArchiveDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to archive %s: %w", path, err)
+	}
+
+	removeEmptyParents(path, targetDir)
+
+	return archivePath, nil
+}
+
+// RemoveRepository deletes the repository at path, which is inside targetDir
+func RemoveRepository(path string, targetDir string) error {
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+
+	removeEmptyParents(path, targetDir)
+
+	return nil
+}
+
+// removeEmptyParents removes the namespace directories of path that became
+// empty, up to but excluding targetDir
+func removeEmptyParents(path string, targetDir string) {
+	targetDir = filepath.Clean(targetDir)
+
+	for dir := filepath.Dir(path); dir != targetDir && len(dir) > len(targetDir); dir = filepath.Dir(dir) {
+		// os.Remove fails for directories that are not empty
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package git
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	git "github.com/go-git/go-git/v5"
+	plumbing "github.com/go-git/go-git/v5/plumbing"
+)
+
+// FindRepositories returns paths of all git repositories under root,
+// including bare repositories (mirrors). Nested repositories inside a found
+// repository and glone's own data directory are not returned.
+func FindRepositories(root string) ([]string, error) {
+	var repos []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if d.Name() == ".git" || d.Name() == DataDir {
+			return filepath.SkipDir
+		}
+
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil || isBareRepository(path) {
+			repos = append(repos, path)
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repos, nil
+}
+
+// isBareRepository reports whether path looks like a bare repository
+func isBareRepository(path string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(path, name)); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HeadCommit returns the commit hash HEAD of the repository at path points to,
+// or an empty string if it can't be resolved
+func HeadCommit(path string) string {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return ""
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+
+	return head.Hash().String()
+}
+
+// BranchHeads returns the commit each local branch of the repository at path
+// points to, or nil if the repository can't be read
+func BranchHeads(path string) map[string]string {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return nil
+	}
+
+	branches, err := repo.Branches()
+	if err != nil {
+		return nil
+	}
+
+	heads := make(map[string]string)
+	_ = branches.ForEach(func(ref *plumbing.Reference) error {
+		heads[ref.Name().Short()] = ref.Hash().String()
+		return nil
+	})
+
+	return heads
+}
+
+// IsRepository reports whether path is a git repository. Unlike LocalStatus
+// it doesn't read the worktree, so it is cheap for large repositories.
+func IsRepository(path string) bool {
+	_, err := git.PlainOpen(path)
+	return err == nil
+}
+
+// Local states of a project relative to the target directory
+const (
+	LocalMissing = "missing"
+	LocalPresent = "present"
+	LocalDirty   = "dirty"
+	LocalNotGit  = "not-git"
+)
+
+// LocalStatus reports whether a git repository exists at path and whether
+// its worktree has local changes. Bare repositories (mirrors) are never dirty.
+func LocalStatus(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return LocalMissing
+	}
+
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return LocalNotGit
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return LocalPresent
+	}
+
+	if clean, err := worktreeClean(repo, wt); err == nil && !clean {
+		return LocalDirty
+	}
+
+	return LocalPresent
+}
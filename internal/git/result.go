@@ -0,0 +1,37 @@
+package git
+
+// Result describes what happened to a project during a run
+type Result string
+
+const (
+	// ResultCloned means the project was cloned
+	ResultCloned Result = "cloned"
+	// ResultSkipped means the project already existed and was left untouched
+	ResultSkipped Result = "skipped"
+	// ResultUpdated means the checked-out branch was fast-forwarded
+	ResultUpdated Result = "updated"
+	// ResultUpToDate means the checked-out branch already matched its upstream
+	ResultUpToDate Result = "up-to-date"
+	// ResultUnchanged means the project had no activity since the last sync
+	// and was not fetched
+	ResultUnchanged Result = "unchanged"
+	// ResultEmpty means the remote repository (usually a wiki) is empty or
+	// doesn't exist yet, so there was nothing to clone
+	ResultEmpty Result = "empty"
+	// ResultDirty means the worktree has local changes and was not updated
+	ResultDirty Result = "dirty"
+	// ResultDiverged means the branch diverged from its upstream and was not updated
+	ResultDiverged Result = "diverged"
+	// ResultNoUpstream means the checked-out branch has no upstream branch,
+	// e.g. a local branch that was never pushed, and was not updated
+	ResultNoUpstream Result = "no-upstream"
+	// ResultConflict means the project path exists but is not a git repository
+	// and was left untouched
+	ResultConflict Result = "conflict"
+	// ResultBackedUp means the project path was not a git repository, it was
+	// moved to the backup directory and the project was cloned
+	ResultBackedUp Result = "backed-up"
+	// ResultReplaced means the project path was not a git repository, it was
+	// removed and the project was cloned
+	ResultReplaced Result = "replaced"
+)
@@ -0,0 +1,121 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	plumbing "github.com/go-git/go-git/v5/plumbing"
+	transport "github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	lfs "github.com/adzpm/glone/internal/lfs"
+	retry "github.com/adzpm/glone/internal/retry"
+)
+
+// transientMessages are fragments of error messages of the git binary and of
+// go-git errors without an exported type that indicate a transient network
+// failure
+var transientMessages = []string{
+	"connection reset",
+	"connection refused",
+	"connection timed out",
+	"timeout exceeded",
+	"early eof",
+	"unexpected eof",
+	"unexpected disconnect",
+	"rpc failed",
+	"could not resolve host",
+	"temporary failure in name resolution",
+	"the remote end hung up unexpectedly",
+}
+
+// transientStatus matches the HTTP status codes worth retrying in errors of
+// the git binary, e.g. "The requested URL returned error: 503"
+var transientStatus = regexp.MustCompile(`returned error: (429|5\d\d)\b`)
+
+// withRetry calls fn and retries it according to the retry policy as long as
+// it fails with a transient error. fn must clean up after a failed attempt.
+func (c *Cloner) withRetry(ctx context.Context, project *Project, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return err
+		}
+
+		transient, resp := isTransient(err)
+		if !transient || attempt >= c.opts.Retry.Retries || ctx.Err() != nil {
+			return err
+		}
+
+		wait := c.opts.Retry.Delay(attempt, resp)
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("Transient error for %s: %v, retrying in %s (%d/%d)",
+				project.Name, err, wait.Round(100*time.Millisecond), attempt+1, c.opts.Retry.Retries)
+		}
+
+		if err := retry.Sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// isTransient reports whether err is worth retrying. It also returns the
+// HTTP response of the failure, if any, to honour Retry-After headers.
+func isTransient(err error) (bool, *http.Response) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false, nil
+	}
+
+	if errors.Is(err, transport.ErrAuthenticationRequired) ||
+		errors.Is(err, transport.ErrAuthorizationFailed) ||
+		errors.Is(err, transport.ErrRepositoryNotFound) ||
+		errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return false, nil
+	}
+
+	// go-git wraps some HTTP errors in UnexpectedError, which doesn't support
+	// unwrapping
+	var httpErr *githttp.Err
+	var unexpected *plumbing.UnexpectedError
+	if errors.As(err, &httpErr) || (errors.As(err, &unexpected) && errors.As(unexpected.Err, &httpErr)) {
+		code := httpErr.StatusCode()
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError, httpErr.Response
+	}
+
+	var lfsErr *lfs.StatusError
+	if errors.As(err, &lfsErr) {
+		code := lfsErr.Response.StatusCode
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError, lfsErr.Response
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true, nil
+	}
+
+	// A connection closed in the middle of a transfer; a plain io.EOF may just
+	// as well be the end of a malformed response and is not retried
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true, nil
+	}
+
+	msg := strings.ToLower(err.Error())
+	if transientStatus.MatchString(msg) {
+		return true, nil
+	}
+
+	for _, fragment := range transientMessages {
+		if strings.Contains(msg, fragment) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,53 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	plumbing "github.com/go-git/go-git/v5/plumbing"
+	transport "github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+func httpErr(code int) *githttp.Err {
+	return &githttp.Err{Response: &http.Response{
+		StatusCode: code,
+		Request:    &http.Request{URL: &url.URL{Scheme: "https", Host: "gitlab.example.com"}},
+	}}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"http 503", httpErr(http.StatusServiceUnavailable), true},
+		{"http 429", httpErr(http.StatusTooManyRequests), true},
+		{"http 404", httpErr(http.StatusNotFound), false},
+		{"wrapped http 502", plumbing.NewUnexpectedError(httpErr(http.StatusBadGateway)), true},
+		{"authentication", fmt.Errorf("error cloning: %w", transport.ErrAuthenticationRequired), false},
+		{"not found", transport.ErrRepositoryNotFound, false},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"unexpected eof", fmt.Errorf("reading pack: %w", io.ErrUnexpectedEOF), true},
+		{"eof", fmt.Errorf("reading config: %w", io.EOF), false},
+		{"git binary 503", errors.New("fatal: unable to access: The requested URL returned error: 503"), true},
+		{"git binary 404", errors.New("fatal: unable to access: The requested URL returned error: 404"), false},
+		{"error code in message", errors.New("object error: 5 entries missing"), false},
+		{"timeout in path", errors.New("reference refs/heads/timeout-fix not found"), false},
+		{"hung up", errors.New("fatal: the remote end hung up unexpectedly"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := isTransient(tt.err); got != tt.want {
+				t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
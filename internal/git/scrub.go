@@ -0,0 +1,65 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+
+	git "github.com/go-git/go-git/v5"
+)
+
+// ScrubRemoteCredentials removes credentials embedded in the HTTP(S) remote
+// URLs of the repository at path, as written by older versions of glone.
+// It returns the names of the remotes that were changed.
+func ScrubRemoteCredentials(path string) ([]string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening repository %s: %w", path, err)
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, fmt.Errorf("error reading config of %s: %w", path, err)
+	}
+
+	var changed []string
+	for name, remote := range cfg.Remotes {
+		scrubbed := false
+		for i, remoteURL := range remote.URLs {
+			clean, ok := stripCredentials(remoteURL)
+			if ok {
+				remote.URLs[i] = clean
+				scrubbed = true
+			}
+		}
+
+		if scrubbed {
+			changed = append(changed, name)
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	if err := repo.Storer.SetConfig(cfg); err != nil {
+		return nil, fmt.Errorf("error writing config of %s: %w", path, err)
+	}
+
+	return changed, nil
+}
+
+// stripCredentials removes user info from an HTTP(S) URL.
+// It reports whether the URL contained any.
+func stripCredentials(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL, false
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return rawURL, false
+	}
+
+	u.User = nil
+	return u.String(), true
+}
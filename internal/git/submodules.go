@@ -0,0 +1,297 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	config "github.com/go-git/go-git/v5/config"
+	plumbing "github.com/go-git/go-git/v5/plumbing"
+	filemode "github.com/go-git/go-git/v5/plumbing/filemode"
+	transport "github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// maxSubmoduleDepth limits how deep nested submodules are updated
+const maxSubmoduleDepth = 10
+
+// withSubmodules initializes and updates the submodules of a project after
+// it was cloned or synced successfully, if submodule support is enabled
+func (c *Cloner) withSubmodules(ctx context.Context, project *Project, targetDir string, token string, result Result, err error) (Result, error) {
+	if !c.opts.Submodules || err != nil || project.Wiki || !fetchedResults[result] {
+		return result, err
+	}
+
+	repo, err := git.PlainOpen(c.ProjectPath(targetDir, project))
+	if err != nil {
+		return "", fmt.Errorf("error opening %s: %w", project.Name, err)
+	}
+
+	remoteURL, _ := c.remote(project, "")
+	if err := c.updateSubmodules(ctx, project, repo, "", remoteURL, targetDir, token, maxSubmoduleDepth); err != nil {
+		return "", fmt.Errorf("error updating submodules of %s: %w", project.Name, err)
+	}
+
+	return result, nil
+}
+
+// updateSubmodules checks out the commits the index of repo records for its
+// submodules, cloning them first if needed, and recurses into their own
+// submodules. dir is the path of repo in the worktree of the project. Relative
+// submodule URLs are resolved against remoteURL, the URL of repo itself.
+func (c *Cloner) updateSubmodules(ctx context.Context, project *Project, repo *git.Repository, dir string, remoteURL string, targetDir string, token string, depth int) error {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	submodules, err := wt.Submodules()
+	if err != nil {
+		return err
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range submodules {
+		cfg := sub.Config()
+		name := path.Join(dir, cfg.Path)
+
+		// Submodules listed in .gitmodules but not committed have nothing to
+		// check out, and initializing them would leave an empty repository
+		// behind in the worktree
+		if entry, err := idx.Entry(cfg.Path); err != nil || entry.Mode != filemode.Submodule {
+			continue
+		}
+
+		url, auth, sibling, err := c.submoduleRemote(project, remoteURL, cfg.URL, targetDir, token)
+		if err != nil {
+			return fmt.Errorf("invalid URL of submodule %s: %w", name, err)
+		}
+		cfg.URL = url
+
+		if err := sub.Init(); err != nil && !errors.Is(err, git.ErrSubmoduleAlreadyInitialized) {
+			return fmt.Errorf("error initializing submodule %s: %w", name, err)
+		}
+
+		status, err := sub.Status()
+		if err != nil {
+			return fmt.Errorf("error getting status of submodule %s: %w", name, err)
+		}
+
+		subRepo, err := sub.Repository()
+		if err != nil {
+			return fmt.Errorf("error opening submodule %s: %w", name, err)
+		}
+
+		if err := setOrigin(subRepo, url); err != nil {
+			return fmt.Errorf("error updating remote of submodule %s: %w", name, err)
+		}
+
+		if status.Current != status.Expected {
+			subWt, err := subRepo.Worktree()
+			if err != nil {
+				return err
+			}
+
+			if clean, err := worktreeClean(subRepo, subWt); err != nil {
+				return fmt.Errorf("error getting status of submodule %s: %w", name, err)
+			} else if !clean {
+				if c.opts.Logger != nil {
+					c.opts.Logger.Warnf("Submodule %s of %s has local changes, skipping", name, project.Name)
+				}
+				continue
+			}
+
+			if c.opts.Logger != nil {
+				c.opts.Logger.Infof("Updating submodule %s of %s", name, project.Name)
+			}
+
+			if err := c.fetchSubmodule(ctx, project, name, subRepo, status.Expected, url, auth, sibling); err != nil {
+				return fmt.Errorf("error fetching submodule %s: %w", name, err)
+			}
+
+			if err := subWt.Checkout(&git.CheckoutOptions{Hash: status.Expected}); err != nil {
+				return fmt.Errorf("error checking out submodule %s: %w", name, err)
+			}
+		}
+
+		if depth > 1 {
+			if err := c.updateSubmodules(ctx, project, subRepo, name, url, targetDir, token, depth-1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetchSubmodule makes sure the commit a submodule should be at is in its
+// repository. It is copied from the clone of the sibling project if there is
+// one, and fetched from url otherwise.
+func (c *Cloner) fetchSubmodule(ctx context.Context, project *Project, name string, repo *git.Repository, commit plumbing.Hash, url string, auth transport.AuthMethod, sibling string) error {
+	if hasCommit(repo, commit) {
+		return nil
+	}
+
+	if sibling != "" {
+		err := repo.FetchContext(ctx, &git.FetchOptions{
+			RemoteURL: sibling,
+			RefSpecs:  []config.RefSpec{"+refs/heads/*:refs/remotes/origin/*"},
+		})
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			if c.opts.Logger != nil {
+				c.opts.Logger.Warnf("Failed to copy %s from %s, fetching it: %v", commit.String()[:8], sibling, err)
+			}
+		} else if hasCommit(repo, commit) {
+			if c.opts.Logger != nil {
+				c.opts.Logger.Infof("Reused %s for submodule %s of %s", sibling, name, project.Name)
+			}
+			return nil
+		}
+	}
+
+	err := c.fetch(ctx, repo, project, &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RemoteURL:  url,
+		Auth:       auth,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
+	}
+
+	// The commit may not be on any branch anymore, ask for it explicitly
+	if !hasCommit(repo, commit) {
+		err := c.fetch(ctx, repo, project, &git.FetchOptions{
+			RemoteName: git.DefaultRemoteName,
+			RemoteURL:  url,
+			Auth:       auth,
+			RefSpecs:   []config.RefSpec{config.RefSpec("+" + commit.String() + ":" + commit.String())},
+		})
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) && !errors.Is(err, git.ErrExactSHA1NotSupported) {
+			return err
+		}
+	}
+
+	if !hasCommit(repo, commit) {
+		return fmt.Errorf("commit %s not found in %s", commit, url)
+	}
+
+	return nil
+}
+
+// submoduleRemote returns the URL and authentication to fetch a submodule
+// from, and the path of the clone of the same project in the target
+// directory, if any. Relative URLs are resolved against parentURL.
+// Submodules on the GitLab instance of the project are fetched with the
+// protocol and credentials of the project; credentials are never sent to
+// other hosts.
+func (c *Cloner) submoduleRemote(project *Project, parentURL string, rawURL string, targetDir string, token string) (string, transport.AuthMethod, string, error) {
+	url, err := resolveSubmoduleURL(parentURL, rawURL)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	pathWithNamespace, ok := gitlabPath(project, url)
+	if !ok {
+		return url, nil, "", nil
+	}
+
+	projectURL, auth := c.remote(project, token)
+	url = strings.TrimSuffix(projectURL, project.PathWithNamespace+".git") + pathWithNamespace + ".git"
+
+	sibling := c.ProjectPath(targetDir, &Project{PathWithNamespace: pathWithNamespace})
+	if _, err := os.Stat(filepath.Join(sibling, ".git")); err != nil {
+		sibling = ""
+	}
+
+	return url, auth, sibling, nil
+}
+
+// resolveSubmoduleURL resolves a submodule URL starting with "./" or "../"
+// against the URL of the parent repository like git does
+func resolveSubmoduleURL(parentURL string, rawURL string) (string, error) {
+	if !strings.HasPrefix(rawURL, "./") && !strings.HasPrefix(rawURL, "../") {
+		return rawURL, nil
+	}
+
+	endpoint, err := transport.NewEndpoint(parentURL)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint.Path = path.Join(endpoint.Path, rawURL)
+	if !strings.HasPrefix(endpoint.Path, "/") {
+		endpoint.Path = "/" + endpoint.Path
+	}
+
+	return endpoint.String(), nil
+}
+
+// gitlabPath returns the path with namespace of the project a repository URL
+// points to if it is on the same GitLab instance as project. HTTP URLs are
+// compared with the HTTP URL of project and SSH URLs with its SSH URL.
+func gitlabPath(project *Project, repoURL string) (string, bool) {
+	target, err := transport.NewEndpoint(repoURL)
+	if err != nil {
+		return "", false
+	}
+	targetPath := "/" + strings.TrimPrefix(target.Path, "/")
+
+	for _, projectURL := range []string{project.HTTPURLToRepo, project.SSHURLToRepo} {
+		endpoint, err := transport.NewEndpoint(projectURL)
+		if err != nil || endpoint.Host != target.Host || isHTTP(endpoint.Protocol) != isHTTP(target.Protocol) {
+			continue
+		}
+
+		// GitLab may be served below a relative URL root
+		projectPath := "/" + strings.TrimPrefix(endpoint.Path, "/")
+		root, ok := strings.CutSuffix(projectPath, project.PathWithNamespace+".git")
+		if !ok || !strings.HasPrefix(targetPath, root) {
+			continue
+		}
+
+		pathWithNamespace := strings.TrimSuffix(strings.TrimPrefix(targetPath, root), ".git")
+		if pathWithNamespace == "" {
+			continue
+		}
+
+		return pathWithNamespace, true
+	}
+
+	return "", false
+}
+
+// isHTTP reports whether an endpoint protocol is HTTP or HTTPS. GitLab may
+// serve HTTP below a relative URL root, but never SSH.
+func isHTTP(protocol string) bool {
+	return protocol == "http" || protocol == "https"
+}
+
+// hasCommit reports whether the commit is stored in repo
+func hasCommit(repo *git.Repository, commit plumbing.Hash) bool {
+	_, err := repo.CommitObject(commit)
+	return err == nil
+}
+
+// setOrigin points the origin remote of repo to url
+func setOrigin(repo *git.Repository, url string) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+
+	remote, ok := cfg.Remotes[git.DefaultRemoteName]
+	if !ok || (len(remote.URLs) == 1 && remote.URLs[0] == url) {
+		return nil
+	}
+
+	remote.URLs = []string{url}
+	return repo.SetConfig(cfg)
+}
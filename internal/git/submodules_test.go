@@ -0,0 +1,109 @@
+package git
+
+import "testing"
+
+func TestResolveSubmoduleURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		parentURL string
+		rawURL    string
+		want      string
+	}{
+		{
+			name:      "absolute",
+			parentURL: "https://gitlab.example.com/group/app.git",
+			rawURL:    "https://github.com/org/lib.git",
+			want:      "https://github.com/org/lib.git",
+		},
+		{
+			name:      "sibling",
+			parentURL: "https://gitlab.example.com/group/app.git",
+			rawURL:    "../lib.git",
+			want:      "https://gitlab.example.com/group/lib.git",
+		},
+		{
+			name:      "other group",
+			parentURL: "https://gitlab.example.com/group/sub/app.git",
+			rawURL:    "../../other/lib.git",
+			want:      "https://gitlab.example.com/group/other/lib.git",
+		},
+		{
+			name:      "below parent",
+			parentURL: "https://gitlab.example.com/group/app.git",
+			rawURL:    "./lib.git",
+			want:      "https://gitlab.example.com/group/app.git/lib.git",
+		},
+		{
+			name:      "above root",
+			parentURL: "https://gitlab.example.com/group/app.git",
+			rawURL:    "../../../lib.git",
+			want:      "https://gitlab.example.com/lib.git",
+		},
+		{
+			name:      "scp-like ssh",
+			parentURL: "git@gitlab.example.com:group/app.git",
+			rawURL:    "../lib.git",
+			want:      "ssh://git@gitlab.example.com/group/lib.git",
+		},
+		{
+			name:      "ssh with port",
+			parentURL: "ssh://git@gitlab.example.com:2222/group/app.git",
+			rawURL:    "../lib.git",
+			want:      "ssh://git@gitlab.example.com:2222/group/lib.git",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSubmoduleURL(tt.parentURL, tt.rawURL)
+			if err != nil {
+				t.Fatalf("resolveSubmoduleURL() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveSubmoduleURL(%q, %q) = %q, want %q", tt.parentURL, tt.rawURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitLabPath(t *testing.T) {
+	project := &Project{
+		PathWithNamespace: "group/app",
+		HTTPURLToRepo:     "https://gitlab.example.com/group/app.git",
+		SSHURLToRepo:      "git@gitlab.example.com:group/app.git",
+	}
+	rooted := &Project{
+		PathWithNamespace: "group/app",
+		HTTPURLToRepo:     "https://example.com/gitlab/group/app.git",
+		SSHURLToRepo:      "ssh://git@example.com:2222/group/app.git",
+	}
+
+	tests := []struct {
+		name    string
+		project *Project
+		url     string
+		want    string
+		ok      bool
+	}{
+		{name: "https", project: project, url: "https://gitlab.example.com/other/lib.git", want: "other/lib", ok: true},
+		{name: "without .git", project: project, url: "https://gitlab.example.com/other/lib", want: "other/lib", ok: true},
+		{name: "subgroup", project: project, url: "https://gitlab.example.com/a/b/c/lib.git", want: "a/b/c/lib", ok: true},
+		{name: "scp-like ssh", project: project, url: "git@gitlab.example.com:other/lib.git", want: "other/lib", ok: true},
+		{name: "ssh", project: project, url: "ssh://git@gitlab.example.com/other/lib.git", want: "other/lib", ok: true},
+		{name: "other host", project: project, url: "https://github.com/other/lib.git", ok: false},
+		{name: "host root", project: project, url: "https://gitlab.example.com/", ok: false},
+		{name: "relative URL root", project: rooted, url: "https://example.com/gitlab/other/lib.git", want: "other/lib", ok: true},
+		{name: "outside relative URL root", project: rooted, url: "https://example.com/other/lib.git", ok: false},
+		{name: "ssh without relative URL root", project: rooted, url: "ssh://git@example.com:2222/other/lib.git", want: "other/lib", ok: true},
+		{name: "invalid", project: project, url: "://", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := gitlabPath(tt.project, tt.url)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("gitlabPath(%q) = %q, %v, want %q, %v", tt.url, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	git "github.com/go-git/go-git/v5"
+	plumbing "github.com/go-git/go-git/v5/plumbing"
+)
+
+// SyncProject fetches an existing clone and fast-forwards its checked-out
+// branch when that is safe. Projects that are not cloned yet are cloned.
+// Repositories with a dirty worktree or a diverged branch are left untouched.
+// It is safe to call concurrently for different projects.
+func (c *Cloner) SyncProject(ctx context.Context, project *Project, targetDir string, token string) (Result, error) {
+	result, err := c.syncProject(ctx, project, targetDir, token)
+	result, err = c.withSubmodules(ctx, project, targetDir, token, result, err)
+	return c.withLFS(ctx, project, targetDir, token, result, err)
+}
+
+func (c *Cloner) syncProject(ctx context.Context, project *Project, targetDir string, token string) (Result, error) {
+	projectPath := c.ProjectPath(targetDir, project)
+
+	repo, err := git.PlainOpen(projectPath)
+	if err != nil {
+		return c.cloneProject(ctx, project, targetDir, token)
+	}
+
+	if c.opts.Mirror {
+		return c.syncMirror(ctx, repo, project, token)
+	}
+
+	// go-git can neither fetch into partial clones nor unshallow, use the git binary
+	if isPartialClone(repo) || (c.opts.Unshallow && isShallow(projectPath)) {
+		return c.syncWithGit(ctx, repo, project, projectPath, token)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("error opening worktree of %s: %w", project.Name, err)
+	}
+
+	clean, err := worktreeClean(repo, wt)
+	if err != nil {
+		return "", fmt.Errorf("error getting status of %s: %w", project.Name, err)
+	}
+
+	if !clean {
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("Project %s has local changes in %s, skipping", project.Name, projectPath)
+		}
+		return ResultDirty, nil
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("error resolving HEAD of %s: %w", project.Name, err)
+	}
+
+	if !head.Name().IsBranch() {
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("Project %s is in detached HEAD state, skipping", project.Name)
+		}
+		return ResultSkipped, nil
+	}
+
+	if c.opts.Logger != nil {
+		c.opts.Logger.Infof("Fetching %s", project.Name)
+	}
+
+	fetchURL, auth := c.remote(project, token)
+	fetchOpts := &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RemoteURL:  fetchURL,
+		Auth:       auth,
+	}
+	err = c.fetch(ctx, repo, project, fetchOpts)
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return "", fmt.Errorf("error fetching %s: %w", project.Name, err)
+	}
+
+	upstream, err := repo.Reference(upstreamRef(repo, head.Name()), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("Branch %s of %s has no upstream branch, skipping", head.Name().Short(), project.Name)
+		}
+		return ResultNoUpstream, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error resolving upstream of %s: %w", project.Name, err)
+	}
+
+	if upstream.Hash() == head.Hash() {
+		return ResultUpToDate, nil
+	}
+
+	local, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return "", fmt.Errorf("error reading local commit of %s: %w", project.Name, err)
+	}
+
+	remote, err := repo.CommitObject(upstream.Hash())
+	if err != nil {
+		return "", fmt.Errorf("error reading upstream commit of %s: %w", project.Name, err)
+	}
+
+	// Local branch is ahead of upstream, nothing to fast-forward
+	if ahead, err := remote.IsAncestor(local); err != nil {
+		return "", fmt.Errorf("error comparing commits of %s: %w", project.Name, err)
+	} else if ahead {
+		return ResultUpToDate, nil
+	}
+
+	canFastForward, err := local.IsAncestor(remote)
+	if err != nil {
+		return "", fmt.Errorf("error comparing commits of %s: %w", project.Name, err)
+	}
+
+	if !canFastForward {
+		if c.opts.Logger != nil {
+			c.opts.Logger.Warnf("Branch %s of %s diverged from upstream, skipping", head.Name().Short(), project.Name)
+		}
+		return ResultDiverged, nil
+	}
+
+	// The worktree is clean, so a hard reset to the upstream commit is a fast-forward
+	if err := wt.Reset(&git.ResetOptions{Commit: remote.Hash, Mode: git.HardReset}); err != nil {
+		return "", fmt.Errorf("error fast-forwarding %s: %w", project.Name, err)
+	}
+
+	if c.opts.Logger != nil {
+		c.opts.Logger.Infof("Updated %s: %s -> %s", project.Name, local.Hash.String()[:8], remote.Hash.String()[:8])
+	}
+
+	return ResultUpdated, nil
+}
+
+// upstreamRef returns the remote-tracking reference of a local branch,
+// falling back to the branch of the same name on origin
+func upstreamRef(repo *git.Repository, branch plumbing.ReferenceName) plumbing.ReferenceName {
+	cfg, err := repo.Config()
+	if err == nil {
+		if b, ok := cfg.Branches[branch.Short()]; ok && b.Remote != "" && b.Merge.IsBranch() {
+			return plumbing.NewRemoteReferenceName(b.Remote, b.Merge.Short())
+		}
+	}
+
+	return plumbing.NewRemoteReferenceName(git.DefaultRemoteName, branch.Short())
+}
+
+// fetch fetches into repo, retrying transient failures
+func (c *Cloner) fetch(ctx context.Context, repo *git.Repository, project *Project, fetchOpts *git.FetchOptions) error {
+	return c.withRetry(ctx, project, func() error {
+		progress := c.progressWriter(project)
+		if progress != nil {
+			fetchOpts.Progress = progress
+		}
+
+		err := repo.FetchContext(ctx, fetchOpts)
+		if progress != nil {
+			progress.Flush()
+		}
+		return err
+	})
+}
@@ -0,0 +1,67 @@
+package git
+
+import (
+	"fmt"
+
+	transport "github.com/go-git/go-git/v5/plumbing/transport"
+	http "github.com/go-git/go-git/v5/plumbing/transport/http"
+	ssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+const (
+	// ProtocolHTTPS clones over HTTP(S) using the access token
+	ProtocolHTTPS = "https"
+	// ProtocolSSH clones over SSH using ssh-agent or a key file
+	ProtocolSSH = "ssh"
+)
+
+const (
+	// sshUser is the user GitLab expects for SSH git operations
+	sshUser = "git"
+	// tokenUser is the user GitLab expects for HTTP(S) git operations with an access token
+	tokenUser = "oauth2"
+)
+
+// remote returns the URL and authentication method to use for a project.
+// Credentials are never part of the URL, so the remote stored in
+// .git/config stays free of secrets.
+func (c *Cloner) remote(project *Project, token string) (string, transport.AuthMethod) {
+	if c.opts.Protocol == ProtocolSSH {
+		return project.SSHURLToRepo, c.sshAuth
+	}
+
+	if token == "" {
+		return project.HTTPURLToRepo, nil
+	}
+
+	return project.HTTPURLToRepo, &http.BasicAuth{
+		Username: tokenUser,
+		Password: token,
+	}
+}
+
+// newSSHAuth creates an SSH authentication method from a key file,
+// or from ssh-agent if no key file is given. Host keys are checked
+// against the known_hosts files (SSH_KNOWN_HOSTS or ~/.ssh/known_hosts).
+func newSSHAuth(keyPath, passphrase string) (transport.AuthMethod, error) {
+	// Fail early if known_hosts can't be loaded; go-git uses the same
+	// files for host key checking when no callback is set explicitly
+	if _, err := ssh.NewKnownHostsDb(); err != nil {
+		return nil, fmt.Errorf("failed to load known_hosts: %w", err)
+	}
+
+	if keyPath != "" {
+		auth, err := ssh.NewPublicKeysFromFile(sshUser, keyPath, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SSH key %s: %w", keyPath, err)
+		}
+		return auth, nil
+	}
+
+	auth, err := ssh.NewSSHAgentAuth(sshUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+	}
+
+	return auth, nil
+}
@@ -2,7 +2,10 @@ package gitlab
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 
 	config "github.com/adzpm/glone/internal/config"
@@ -26,7 +29,11 @@ func NewClient(cfg *config.Config, opts ...ClientOption) (*Client, error) {
 		baseURL = fmt.Sprintf("https://%s", cfg.GitLabHost)
 	}
 
-	client, err := gitlab.NewClient(cfg.GitLabToken, gitlab.WithBaseURL(baseURL))
+	client, err := gitlab.NewClient(cfg.GitLabToken,
+		gitlab.WithBaseURL(baseURL),
+		gitlab.WithCustomRetryMax(options.Retry.Retries),
+		gitlab.WithCustomBackoff(backoff(options)),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
 	}
@@ -48,3 +55,22 @@ func NewClient(cfg *config.Config, opts ...ClientOption) (*Client, error) {
 		logger: options,
 	}, nil
 }
+
+// backoff returns the retry delay callback of the API client, which
+// follows the retry policy and logs every retry
+func backoff(options *ClientOptions) retryablehttp.Backoff {
+	return func(min, max time.Duration, attempt int, resp *http.Response) time.Duration {
+		wait := options.Retry.HTTPBackoff(min, max, attempt, resp)
+
+		if options.Logger != nil {
+			reason := "connection error"
+			if resp != nil {
+				reason = resp.Status
+			}
+			options.Logger.Warnf("GitLab API request failed (%s), retrying in %s (%d/%d)",
+				reason, wait.Round(100*time.Millisecond), attempt+1, options.Retry.Retries)
+		}
+
+		return wait
+	}
+}
@@ -0,0 +1,121 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// The list methods below return items as the API sent them, without decoding
+// them into the structs of the client library, so that exports keep fields
+// the library doesn't know about.
+
+// eachPage calls fn with every page of a list endpoint, starting with the
+// first. opt returns the query options of a page.
+func (c *Client) eachPage(ctx context.Context, path string, opt func(page gitlab.ListOptions) any, fn func([]json.RawMessage) error) error {
+	page := gitlab.ListOptions{
+		PerPage: 100,
+		Page:    1,
+	}
+
+	for {
+		req, err := c.NewRequest(http.MethodGet, path, opt(page), []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+		if err != nil {
+			return err
+		}
+
+		var items []json.RawMessage
+		resp, err := c.Do(req, &items)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(items); err != nil {
+			return err
+		}
+
+		if resp.NextPage == 0 {
+			return nil
+		}
+
+		page.Page = resp.NextPage
+	}
+}
+
+// ListIssues calls fn with every page of issues of a project, oldest update
+// first. If updatedAfter is set, only issues updated since then are listed.
+func (c *Client) ListIssues(ctx context.Context, projectID int, updatedAfter *time.Time, fn func([]json.RawMessage) error) error {
+	return c.eachPage(ctx, fmt.Sprintf("projects/%d/issues", projectID), func(page gitlab.ListOptions) any {
+		return &gitlab.ListProjectIssuesOptions{
+			ListOptions:  page,
+			UpdatedAfter: updatedAfter,
+			OrderBy:      gitlab.Ptr("updated_at"),
+			Sort:         gitlab.Ptr("asc"),
+		}
+	}, fn)
+}
+
+// ListMergeRequests calls fn with every page of merge requests of a project,
+// oldest update first. If updatedAfter is set, only merge requests updated
+// since then are listed.
+func (c *Client) ListMergeRequests(ctx context.Context, projectID int, updatedAfter *time.Time, fn func([]json.RawMessage) error) error {
+	return c.eachPage(ctx, fmt.Sprintf("projects/%d/merge_requests", projectID), func(page gitlab.ListOptions) any {
+		return &gitlab.ListProjectMergeRequestsOptions{
+			ListOptions:  page,
+			UpdatedAfter: updatedAfter,
+			OrderBy:      gitlab.Ptr("updated_at"),
+			Sort:         gitlab.Ptr("asc"),
+		}
+	}, fn)
+}
+
+// ListLabels calls fn with every page of labels of a project, including
+// the labels of its ancestor groups
+func (c *Client) ListLabels(ctx context.Context, projectID int, fn func([]json.RawMessage) error) error {
+	return c.eachPage(ctx, fmt.Sprintf("projects/%d/labels", projectID), func(page gitlab.ListOptions) any {
+		return &gitlab.ListLabelsOptions{
+			ListOptions:           page,
+			IncludeAncestorGroups: gitlab.Ptr(true),
+		}
+	}, fn)
+}
+
+// ListMilestones calls fn with every page of milestones of a project,
+// including the milestones of its ancestor groups
+func (c *Client) ListMilestones(ctx context.Context, projectID int, fn func([]json.RawMessage) error) error {
+	return c.eachPage(ctx, fmt.Sprintf("projects/%d/milestones", projectID), func(page gitlab.ListOptions) any {
+		return &gitlab.ListMilestonesOptions{
+			ListOptions:      page,
+			IncludeAncestors: gitlab.Ptr(true),
+		}
+	}, fn)
+}
+
+// IssueDiscussions returns all discussions of an issue with their notes
+func (c *Client) IssueDiscussions(ctx context.Context, projectID int, issueIID int) ([]json.RawMessage, error) {
+	return c.allPages(ctx, fmt.Sprintf("projects/%d/issues/%d/discussions", projectID, issueIID))
+}
+
+// MergeRequestDiscussions returns all discussions of a merge request with
+// their notes
+func (c *Client) MergeRequestDiscussions(ctx context.Context, projectID int, mergeRequestIID int) ([]json.RawMessage, error) {
+	return c.allPages(ctx, fmt.Sprintf("projects/%d/merge_requests/%d/discussions", projectID, mergeRequestIID))
+}
+
+// allPages returns the items of all pages of a list endpoint without options
+func (c *Client) allPages(ctx context.Context, path string) ([]json.RawMessage, error) {
+	var all []json.RawMessage
+
+	err := c.eachPage(ctx, path, func(page gitlab.ListOptions) any {
+		return &page
+	}, func(items []json.RawMessage) error {
+		all = append(all, items...)
+		return nil
+	})
+
+	return all, err
+}
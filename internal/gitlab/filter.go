@@ -0,0 +1,106 @@
+package gitlab
+
+import (
+	"slices"
+	"strings"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	config "github.com/adzpm/glone/internal/config"
+)
+
+// archivedOption converts the archived filter mode to the API parameter.
+// GitLab returns only archived projects for true, only active ones for
+// false and both when the parameter is omitted.
+func archivedOption(mode string) *bool {
+	switch mode {
+	case config.ArchivedOnly:
+		return gitlab.Ptr(true)
+	case config.ArchivedExclude:
+		return gitlab.Ptr(false)
+	default:
+		return nil
+	}
+}
+
+// applyFilter pushes the filters supported by the projects API down into opt
+func applyFilter(opt *gitlab.ListProjectsOptions, filter *config.Filter) {
+	opt.Archived = archivedOption(filter.Archived)
+
+	if filter.Visibility != "" {
+		opt.Visibility = gitlab.Ptr(gitlab.VisibilityValue(filter.Visibility))
+	}
+
+	if len(filter.Topics) > 0 {
+		opt.Topic = gitlab.Ptr(strings.Join(filter.Topics, ","))
+	}
+
+	if !filter.ActiveSince.IsZero() {
+		opt.LastActivityAfter = gitlab.Ptr(filter.ActiveSince)
+	}
+
+	if filter.MinAccessLevel > 0 {
+		opt.MinAccessLevel = gitlab.Ptr(gitlab.AccessLevelValue(filter.MinAccessLevel))
+	}
+}
+
+// applyGroupFilter pushes the filters supported by the group projects API down into opt
+func applyGroupFilter(opt *gitlab.ListGroupProjectsOptions, filter *config.Filter) {
+	opt.Archived = archivedOption(filter.Archived)
+
+	if filter.Visibility != "" {
+		opt.Visibility = gitlab.Ptr(gitlab.VisibilityValue(filter.Visibility))
+	}
+
+	if len(filter.Topics) > 0 {
+		opt.Topic = gitlab.Ptr(strings.Join(filter.Topics, ","))
+	}
+
+	if filter.MinAccessLevel > 0 {
+		opt.MinAccessLevel = gitlab.Ptr(gitlab.AccessLevelValue(filter.MinAccessLevel))
+	}
+}
+
+// matchFilter applies all filters client-side. Filters already pushed down
+// to the API are checked again, which is cheap and guards against API
+// versions that ignore some parameters.
+func matchFilter(p *gitlab.Project, filter *config.Filter) bool {
+	switch filter.Archived {
+	case config.ArchivedOnly:
+		if !p.Archived {
+			return false
+		}
+	case config.ArchivedExclude:
+		if p.Archived {
+			return false
+		}
+	}
+
+	if filter.Visibility != "" && string(p.Visibility) != filter.Visibility {
+		return false
+	}
+
+	for _, topic := range filter.Topics {
+		if !slices.Contains(p.Topics, topic) {
+			return false
+		}
+	}
+
+	if !filter.ActiveSince.IsZero() && (p.LastActivityAt == nil || p.LastActivityAt.Before(filter.ActiveSince)) {
+		return false
+	}
+
+	return filter.MatchPath(p.PathWithNamespace)
+}
+
+// filterProjects returns projects that match the filter
+func filterProjects(projects []*gitlab.Project, filter *config.Filter) []*gitlab.Project {
+	filtered := make([]*gitlab.Project, 0, len(projects))
+	for _, p := range projects {
+		if matchFilter(p, filter) {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
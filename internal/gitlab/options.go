@@ -2,13 +2,16 @@ package gitlab
 
 import (
 	logger "github.com/adzpm/glone/internal/logger"
+	retry "github.com/adzpm/glone/internal/retry"
 )
 
 // ClientOptions holds GitLab client configuration options
 type ClientOptions struct {
-	Logger   logger.Logger
-	BaseURL  string
-	SkipAuth bool
+	Logger     logger.Logger
+	BaseURL    string
+	SkipAuth   bool
+	Retry      retry.Policy
+	Statistics bool
 }
 
 // ClientOption is a function that modifies ClientOptions
@@ -35,11 +38,30 @@ func WithSkipAuth(skip bool) ClientOption {
 	}
 }
 
+// WithRetryPolicy sets how failed API requests (429 and 5xx responses,
+// connection errors) are retried
+func WithRetryPolicy(policy retry.Policy) ClientOption {
+	return func(o *ClientOptions) {
+		o.Retry = policy
+	}
+}
+
+// WithStatistics requests project statistics such as the repository size.
+// The group projects API doesn't support them, so they are only returned
+// when listing without a group.
+func WithStatistics(statistics bool) ClientOption {
+	return func(o *ClientOptions) {
+		o.Statistics = statistics
+	}
+}
+
 // defaultClientOptions returns default client options
 func defaultClientOptions() *ClientOptions {
 	return &ClientOptions{
-		Logger:   nil,
-		BaseURL:  "",
-		SkipAuth: false,
+		Logger:     nil,
+		BaseURL:    "",
+		SkipAuth:   false,
+		Retry:      retry.DefaultPolicy(),
+		Statistics: false,
 	}
 }
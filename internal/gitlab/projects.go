@@ -4,17 +4,45 @@ import (
 	"fmt"
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	config "github.com/adzpm/glone/internal/config"
 )
 
-// GetAllProjects retrieves all accessible projects, optionally filtered by group
-func (c *Client) GetAllProjects(groupName string) ([]*gitlab.Project, error) {
-	if groupName != "" {
-		return c.getGroupProjects(groupName)
+// GetAllProjects retrieves the projects of the selection that match the filter.
+// Projects of several sources are de-duplicated by ID. A nil or empty selection
+// selects all accessible projects, a nil filter selects all projects.
+func (c *Client) GetAllProjects(selection *config.Selection, filter *config.Filter) ([]*gitlab.Project, error) {
+	if selection == nil {
+		selection = &config.Selection{}
+	}
+
+	if filter == nil {
+		filter = &config.Filter{}
 	}
-	return c.getAllAccessibleProjects()
+
+	var (
+		projects []*gitlab.Project
+		err      error
+	)
+
+	if !selection.IsEmpty() {
+		projects, err = c.getSelectedProjects(selection, filter)
+	} else {
+		projects, err = c.getAllAccessibleProjects(filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := filterProjects(projects, filter)
+	if c.logger.Logger != nil && len(filtered) != len(projects) {
+		c.logger.Logger.Infof("Filtered out %d projects", len(projects)-len(filtered))
+	}
+
+	return filtered, nil
 }
 
-func (c *Client) getGroupProjects(groupName string) ([]*gitlab.Project, error) {
+func (c *Client) getGroupProjects(groupName string, filter *config.Filter) ([]*gitlab.Project, error) {
 	var allProjects []*gitlab.Project
 
 	// First, try to get the group to verify it exists and get its ID/path
@@ -36,6 +64,7 @@ func (c *Client) getGroupProjects(groupName string) ([]*gitlab.Project, error) {
 		},
 		IncludeSubGroups: gitlab.Ptr(true), // Include projects from subgroups
 	}
+	applyGroupFilter(groupOpt, filter)
 
 	// Use group ID directly (as int, which is more reliable)
 	if c.logger.Logger != nil {
@@ -101,7 +130,7 @@ func (c *Client) getGroupProjects(groupName string) ([]*gitlab.Project, error) {
 	return allProjects, nil
 }
 
-func (c *Client) getAllAccessibleProjects() ([]*gitlab.Project, error) {
+func (c *Client) getAllAccessibleProjects(filter *config.Filter) ([]*gitlab.Project, error) {
 	var allProjects []*gitlab.Project
 
 	// Try multiple approaches to get all projects
@@ -115,8 +144,11 @@ func (c *Client) getAllAccessibleProjects() ([]*gitlab.Project, error) {
 			PerPage: 100,
 			Page:    1,
 		},
-		Archived: gitlab.Ptr(true), // Include archived
-		Simple:   gitlab.Ptr(false),
+		Simple: gitlab.Ptr(false),
+	}
+	applyFilter(opt, filter)
+	if c.logger.Statistics {
+		opt.Statistics = gitlab.Ptr(true)
 	}
 
 	for {
@@ -150,10 +182,13 @@ func (c *Client) getAllAccessibleProjects() ([]*gitlab.Project, error) {
 				PerPage: 100,
 				Page:    1,
 			},
-			Archived:   gitlab.Ptr(true),
 			Simple:     gitlab.Ptr(false),
 			Membership: gitlab.Ptr(true),
 		}
+		applyFilter(memberOpt, filter)
+		if c.logger.Statistics {
+			memberOpt.Statistics = gitlab.Ptr(true)
+		}
 
 		memberProjects := make(map[int]*gitlab.Project)
 		for _, p := range allProjects {
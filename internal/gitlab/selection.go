@@ -0,0 +1,120 @@
+package gitlab
+
+import (
+	"fmt"
+
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+
+	config "github.com/adzpm/glone/internal/config"
+)
+
+// listFunc lists one page of projects
+type listFunc func(opt *gitlab.ListProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error)
+
+// getSelectedProjects retrieves the projects of all sources of the selection,
+// de-duplicated by ID
+func (c *Client) getSelectedProjects(selection *config.Selection, filter *config.Filter) ([]*gitlab.Project, error) {
+	var allProjects []*gitlab.Project
+	seen := make(map[int]bool)
+
+	add := func(projects []*gitlab.Project) {
+		for _, p := range projects {
+			if !seen[p.ID] {
+				seen[p.ID] = true
+				allProjects = append(allProjects, p)
+			}
+		}
+	}
+
+	for _, groupName := range selection.Groups {
+		projects, err := c.getGroupProjects(groupName, filter)
+		if err != nil {
+			return nil, err
+		}
+		add(projects)
+	}
+
+	if selection.Owned {
+		projects, err := c.listProjects("owned", filter, func(opt *gitlab.ListProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+			opt.Owned = gitlab.Ptr(true)
+			return c.Projects.ListProjects(opt)
+		})
+		if err != nil {
+			return nil, err
+		}
+		add(projects)
+	}
+
+	if selection.Starred {
+		projects, err := c.listProjects("starred", filter, func(opt *gitlab.ListProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+			opt.Starred = gitlab.Ptr(true)
+			return c.Projects.ListProjects(opt)
+		})
+		if err != nil {
+			return nil, err
+		}
+		add(projects)
+	}
+
+	users := selection.Users
+	if selection.Personal {
+		user, _, err := c.Users.CurrentUser()
+		if err != nil {
+			return nil, fmt.Errorf("error getting current user: %w", err)
+		}
+		users = append([]string{user.Username}, users...)
+	}
+
+	for _, username := range users {
+		projects, err := c.listProjects("user "+username, filter, func(opt *gitlab.ListProjectsOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+			return c.Projects.ListUserProjects(username, opt)
+		})
+		if err != nil {
+			return nil, err
+		}
+		add(projects)
+	}
+
+	return allProjects, nil
+}
+
+// listProjects retrieves all pages of a project list with the filter applied
+func (c *Client) listProjects(source string, filter *config.Filter, list listFunc) ([]*gitlab.Project, error) {
+	var allProjects []*gitlab.Project
+
+	opt := &gitlab.ListProjectsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+			Page:    1,
+		},
+		Simple: gitlab.Ptr(false),
+	}
+	applyFilter(opt, filter)
+	if c.logger.Statistics {
+		opt.Statistics = gitlab.Ptr(true)
+	}
+
+	if c.logger.Logger != nil {
+		c.logger.Logger.Infof("Fetching %s projects...", source)
+	}
+
+	for {
+		projects, resp, err := list(opt)
+		if err != nil {
+			return nil, fmt.Errorf("error getting %s projects: %w", source, err)
+		}
+
+		if c.logger.Logger != nil {
+			c.logger.Logger.Infof("Page %d: found %d %s projects", opt.Page, len(projects), source)
+		}
+		allProjects = append(allProjects, projects...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opt.Page = resp.NextPage
+	}
+
+	return allProjects, nil
+}
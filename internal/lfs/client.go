@@ -0,0 +1,242 @@
+package lfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mediaType is the content type of LFS batch API requests and responses
+const mediaType = "application/vnd.git-lfs+json"
+
+// batchSize is the number of objects requested per batch API call
+const batchSize = 100
+
+// tokenUser is the user GitLab expects for LFS requests with an access token
+const tokenUser = "oauth2"
+
+// StatusError is returned for unsuccessful responses of the LFS server
+type StatusError struct {
+	Op       string
+	Response *http.Response
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("lfs %s: HTTP %d", e.Op, e.Response.StatusCode)
+}
+
+// Client downloads LFS objects of a repository through the batch API
+type Client struct {
+	endpoint string
+	token    string
+	http     *http.Client
+}
+
+// NewClient creates a client for the LFS server of the repository at the
+// HTTP(S) URL repoURL, authenticating with a GitLab access token
+func NewClient(repoURL string, token string) *Client {
+	return &Client{
+		endpoint: strings.TrimSuffix(repoURL, ".git") + ".git/info/lfs",
+		token:    token,
+		http:     &http.Client{},
+	}
+}
+
+// sameHost reports whether u has the scheme and host of the LFS endpoint
+func (c *Client) sameHost(u *url.URL) bool {
+	endpoint, err := url.Parse(c.endpoint)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Scheme, endpoint.Scheme) && strings.EqualFold(u.Host, endpoint.Host)
+}
+
+// ObjectPath returns the path of an object in the LFS directory of a
+// repository. oid must be valid, see ValidOID.
+func ObjectPath(lfsDir string, oid string) string {
+	return filepath.Join(lfsDir, "objects", oid[0:2], oid[2:4], oid)
+}
+
+// HasObject reports whether the object of p is stored in lfsDir
+func HasObject(lfsDir string, p Pointer) bool {
+	if !ValidOID(p.OID) {
+		return false
+	}
+
+	info, err := os.Stat(ObjectPath(lfsDir, p.OID))
+	return err == nil && info.Size() == p.Size
+}
+
+type batchRequest struct {
+	Operation string    `json:"operation"`
+	Transfers []string  `json:"transfers"`
+	Objects   []Pointer `json:"objects"`
+}
+
+type batchResponse struct {
+	Objects []struct {
+		Pointer
+		Actions struct {
+			Download *struct {
+				Href   string            `json:"href"`
+				Header map[string]string `json:"header"`
+			} `json:"download"`
+		} `json:"actions"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"objects"`
+}
+
+// Download stores the objects of pointers in lfsDir. Objects that are
+// already stored are skipped, so a failed download can be resumed.
+func (c *Client) Download(ctx context.Context, pointers []Pointer, lfsDir string) error {
+	var missing []Pointer
+	for _, p := range pointers {
+		if !ValidOID(p.OID) {
+			return fmt.Errorf("lfs object %q: invalid object ID", p.OID)
+		}
+
+		if !HasObject(lfsDir, p) {
+			missing = append(missing, p)
+		}
+	}
+
+	for start := 0; start < len(missing); start += batchSize {
+		batch := missing[start:min(start+batchSize, len(missing))]
+		if err := c.downloadBatch(ctx, batch, lfsDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) downloadBatch(ctx context.Context, pointers []Pointer, lfsDir string) error {
+	body, err := json.Marshal(batchRequest{
+		Operation: "download",
+		Transfers: []string{"basic"},
+		Objects:   pointers,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/objects/batch", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", mediaType)
+	req.Header.Set("Content-Type", mediaType)
+	if c.token != "" {
+		req.SetBasicAuth(tokenUser, c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{Op: "batch", Response: resp}
+	}
+
+	var batch batchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return fmt.Errorf("lfs batch: invalid response: %w", err)
+	}
+
+	// Only objects that were requested are downloaded, the server response
+	// is not trusted to name valid object IDs
+	requested := make(map[Pointer]bool, len(pointers))
+	for _, p := range pointers {
+		requested[p] = true
+	}
+
+	for _, obj := range batch.Objects {
+		if !requested[obj.Pointer] {
+			return fmt.Errorf("lfs batch: unexpected object %q of size %d in response", obj.OID, obj.Size)
+		}
+
+		if obj.Error != nil {
+			return fmt.Errorf("lfs object %s: %s (%d)", obj.OID, obj.Error.Message, obj.Error.Code)
+		}
+
+		download := obj.Actions.Download
+		if download == nil {
+			// The server already has the object and nothing to download
+			continue
+		}
+
+		if err := c.downloadObject(ctx, obj.Pointer, download.Href, download.Header, lfsDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// downloadObject downloads a single object and verifies its size and hash
+// before moving it into place
+func (c *Client) downloadObject(ctx context.Context, p Pointer, href string, header map[string]string, lfsDir string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, href, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	// Without explicit headers the object is served by GitLab itself. Object
+	// storage gets pre-signed URLs instead, the token is never sent there.
+	if len(header) == 0 && c.token != "" && c.sameHost(req.URL) {
+		req.SetBasicAuth(tokenUser, c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{Op: "download", Response: resp}
+	}
+
+	path := ObjectPath(lfsDir, p.OID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), p.OID+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("lfs object %s: %w", p.OID, err)
+	}
+
+	if n != p.Size || hex.EncodeToString(hash.Sum(nil)) != p.OID {
+		return fmt.Errorf("lfs object %s: content doesn't match pointer", p.OID)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,83 @@
+package lfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestDownloadCredentials(t *testing.T) {
+	content := []byte("large file content\n")
+	sum := sha256.Sum256(content)
+	p := Pointer{OID: hex.EncodeToString(sum[:]), Size: int64(len(content))}
+
+	tests := []struct {
+		name     string
+		external bool
+		wantAuth bool
+	}{
+		{name: "served by GitLab", external: false, wantAuth: true},
+		{name: "served by object storage", external: true, wantAuth: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAuth bool
+			objects := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _, gotAuth = r.BasicAuth()
+				w.Write(content)
+			})
+
+			storage := httptest.NewServer(objects)
+			defer storage.Close()
+
+			mux := http.NewServeMux()
+			mux.Handle("GET /object", objects)
+
+			var gitlab *httptest.Server
+			mux.HandleFunc("POST /group/app.git/info/lfs/objects/batch", func(w http.ResponseWriter, r *http.Request) {
+				if _, _, ok := r.BasicAuth(); !ok {
+					t.Error("batch request without credentials")
+				}
+
+				href := gitlab.URL + "/object"
+				if tt.external {
+					href = storage.URL + "/object"
+				}
+
+				w.Header().Set("Content-Type", mediaType)
+				json.NewEncoder(w).Encode(map[string]any{
+					"objects": []any{map[string]any{
+						"oid":  p.OID,
+						"size": p.Size,
+						"actions": map[string]any{
+							"download": map[string]any{"href": href},
+						},
+					}},
+				})
+			})
+
+			gitlab = httptest.NewServer(mux)
+			defer gitlab.Close()
+
+			lfsDir := t.TempDir()
+			client := NewClient(gitlab.URL+"/group/app.git", "token")
+			if err := client.Download(context.Background(), []Pointer{p}, lfsDir); err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+
+			if gotAuth != tt.wantAuth {
+				t.Errorf("object request with credentials = %v, want %v", gotAuth, tt.wantAuth)
+			}
+
+			if data, err := os.ReadFile(ObjectPath(lfsDir, p.OID)); err != nil || string(data) != string(content) {
+				t.Errorf("stored object = %q, %v, want %q", data, err, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package lfs
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// MaxPointerSize is the maximal size of a pointer file; larger files are
+// never pointers
+const MaxPointerSize = 1024
+
+// pointerVersion is the first line of every pointer file
+const pointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+// Pointer references an LFS object by its SHA-256 and size
+type Pointer struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+// ValidOID reports whether oid is a SHA-256 in lowercase hex, the only form
+// accepted as object ID. Object IDs are used in file paths, so anything else
+// is rejected.
+func ValidOID(oid string) bool {
+	if len(oid) != 64 {
+		return false
+	}
+
+	for _, c := range oid {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParsePointer parses the contents of a pointer file. It reports false if
+// data is not a valid pointer.
+func ParsePointer(data []byte) (Pointer, bool) {
+	if len(data) > MaxPointerSize || !bytes.HasPrefix(data, []byte(pointerVersion)) {
+		return Pointer{}, false
+	}
+
+	var (
+		p       Pointer
+		hasSize bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		switch key {
+		case "oid":
+			oid, ok := strings.CutPrefix(value, "sha256:")
+			if !ok || !ValidOID(oid) {
+				return Pointer{}, false
+			}
+			p.OID = oid
+		case "size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || size < 0 {
+				return Pointer{}, false
+			}
+			p.Size = size
+			hasSize = true
+		}
+	}
+
+	if p.OID == "" || !hasSize {
+		return Pointer{}, false
+	}
+
+	return p, true
+}
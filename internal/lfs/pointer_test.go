@@ -0,0 +1,96 @@
+package lfs
+
+import (
+	"strings"
+	"testing"
+)
+
+const testOID = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Pointer
+		ok   bool
+	}{
+		{
+			name: "valid",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:" + testOID + "\nsize 12345\n",
+			want: Pointer{OID: testOID, Size: 12345},
+			ok:   true,
+		},
+		{
+			name: "extension keys are ignored",
+			data: "version https://git-lfs.github.com/spec/v1\next-0-foo sha256:" + testOID + "\noid sha256:" + testOID + "\nsize 0\n",
+			want: Pointer{OID: testOID, Size: 0},
+			ok:   true,
+		},
+		{
+			name: "not a pointer",
+			data: "hello world\n",
+		},
+		{
+			name: "missing size",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:" + testOID + "\n",
+		},
+		{
+			name: "missing oid",
+			data: "version https://git-lfs.github.com/spec/v1\nsize 10\n",
+		},
+		{
+			name: "negative size",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:" + testOID + "\nsize -1\n",
+		},
+		{
+			name: "other hash",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha1:" + testOID[:40] + "\nsize 10\n",
+		},
+		{
+			name: "short oid",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:" + testOID[:63] + "\nsize 10\n",
+		},
+		{
+			name: "uppercase oid",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:" + strings.ToUpper(testOID) + "\nsize 10\n",
+		},
+		{
+			name: "path traversal",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:../../../../../../etc/passwd" + strings.Repeat("a", 34) + "\nsize 10\n",
+		},
+		{
+			name: "too large",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:" + testOID + "\nsize 10\n" + strings.Repeat("x", MaxPointerSize),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParsePointer([]byte(tt.data))
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ParsePointer() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestValidOID(t *testing.T) {
+	tests := []struct {
+		oid  string
+		want bool
+	}{
+		{testOID, true},
+		{"", false},
+		{"ab", false},
+		{testOID + "0", false},
+		{strings.ToUpper(testOID), false},
+		{"../" + testOID[3:], false},
+		{strings.Repeat("g", 64), false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidOID(tt.oid); got != tt.want {
+			t.Errorf("ValidOID(%q) = %v, want %v", tt.oid, got, tt.want)
+		}
+	}
+}
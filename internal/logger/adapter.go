@@ -19,12 +19,12 @@ func NewAdapter(l *log.Logger) *Adapter {
 
 // New creates and initializes a new logger instance with options
 func New(opts ...Option) Logger {
-	options := Options{}
+	options := Options{Output: os.Stdout}
 	for _, opt := range opts {
 		opt(&options)
 	}
 
-	l := log.NewWithOptions(os.Stdout, log.Options{
+	l := log.NewWithOptions(options.Output, log.Options{
 		Prefix:          "glone",
 		TimeFormat:      time.Kitchen,
 		Level:           log.DebugLevel,
@@ -1,5 +1,16 @@
 package logger
 
-type Options struct{}
+import "io"
+
+type Options struct {
+	Output io.Writer
+}
 
 type Option func(*Options)
+
+// WithOutput sets where log messages are written (default: stdout)
+func WithOutput(w io.Writer) Option {
+	return func(o *Options) {
+		o.Output = w
+	}
+}
@@ -2,6 +2,7 @@ package netrc
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strings"
 
@@ -29,7 +30,9 @@ func NewLoader(opts ...LoaderOption) (*Loader, error) {
 	return &Loader{opts: options}, nil
 }
 
-// LoadCredentials loads GitLab credentials from .netrc file
+// LoadCredentials loads GitLab credentials from .netrc file.
+// If a host is set, the machine matching it exactly or the default entry is used;
+// otherwise the first machine with "gitlab" in its name is used.
 func (l *Loader) LoadCredentials() (*config.Config, error) {
 	if _, err := os.Stat(l.opts.NetrcPath); os.IsNotExist(err) {
 		return nil, nil
@@ -40,6 +43,10 @@ func (l *Loader) LoadCredentials() (*config.Config, error) {
 		return nil, fmt.Errorf("failed to parse .netrc: %w", err)
 	}
 
+	if l.opts.Host != "" {
+		return l.hostCredentials(n)
+	}
+
 	gitlabMachines, err := findGitLabCredentials(n)
 	if err != nil {
 		return nil, fmt.Errorf("error searching for GitLab credentials: %w", err)
@@ -79,10 +86,73 @@ func (l *Loader) LoadCredentials() (*config.Config, error) {
 	}, nil
 }
 
+// hostCredentials loads credentials of the machine matching the configured
+// host exactly, falling back to the default entry
+func (l *Loader) hostCredentials(n *netrc.Netrc) (*config.Config, error) {
+	machine := findHostMachine(n, l.opts.Host)
+	if machine == nil {
+		return nil, nil
+	}
+
+	login := machine.Get("login")
+	password := machine.Get("password")
+
+	if login == "" || password == "" {
+		return nil, fmt.Errorf("incomplete credentials for %s in .netrc", machine.Name)
+	}
+
+	if machine.IsDefault && l.opts.Logger != nil {
+		l.opts.Logger.Infof("No .netrc entry for %s, using default entry", l.opts.Host)
+	}
+
+	return &config.Config{
+		GitLabHost:  l.opts.Host,
+		GitLabUser:  login,
+		GitLabToken: password,
+	}, nil
+}
+
+// findHostMachine returns the machine named host, or named like host without
+// its port, or the default entry if there is no such machine
+func findHostMachine(n *netrc.Netrc, host string) *netrc.Machine {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+
+	var byHostname, fallback *netrc.Machine
+	for _, machine := range n.Machines() {
+		switch {
+		case machine.IsDefault:
+			if fallback == nil {
+				fallback = machine
+			}
+		case strings.EqualFold(machine.Name, host):
+			return machine
+		case strings.EqualFold(machine.Name, hostname):
+			if byHostname == nil {
+				byHostname = machine
+			}
+		}
+	}
+
+	if byHostname != nil {
+		return byHostname
+	}
+
+	return fallback
+}
+
+// findGitLabCredentials returns machines whose name contains "gitlab".
+// It is used when no host is configured.
 func findGitLabCredentials(n *netrc.Netrc) ([]*netrc.Machine, error) {
 	var gitlabMachines []*netrc.Machine
 
 	for _, machine := range n.Machines() {
+		if machine.IsDefault {
+			continue
+		}
+
 		machineName := strings.ToLower(machine.Name)
 		if strings.Contains(machineName, "gitlab") {
 			gitlabMachines = append(gitlabMachines, machine)
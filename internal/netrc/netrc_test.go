@@ -0,0 +1,88 @@
+package netrc
+
+import (
+	"testing"
+
+	netrc "github.com/jdx/go-netrc"
+)
+
+func TestFindHostMachine(t *testing.T) {
+	tests := []struct {
+		name  string
+		netrc string
+		host  string
+		want  string
+	}{
+		{
+			name:  "exact host",
+			netrc: "machine gitlab.com login a password x\nmachine gitlab.example.com login b password y\n",
+			host:  "gitlab.example.com",
+			want:  "b",
+		},
+		{
+			name:  "case insensitive",
+			netrc: "machine GitLab.Example.com login b password y\n",
+			host:  "gitlab.example.com",
+			want:  "b",
+		},
+		{
+			name:  "host with port",
+			netrc: "machine gitlab.example.com:8443 login a password x\n",
+			host:  "gitlab.example.com:8443",
+			want:  "a",
+		},
+		{
+			name:  "host without port",
+			netrc: "machine gitlab.example.com login a password x\n",
+			host:  "gitlab.example.com:8443",
+			want:  "a",
+		},
+		{
+			name:  "host with port preferred",
+			netrc: "machine gitlab.example.com login a password x\nmachine gitlab.example.com:8443 login b password y\n",
+			host:  "gitlab.example.com:8443",
+			want:  "b",
+		},
+		{
+			name:  "other port",
+			netrc: "machine gitlab.example.com:8443 login a password x\n",
+			host:  "gitlab.example.com",
+			want:  "",
+		},
+		{
+			name:  "default",
+			netrc: "machine gitlab.com login a password x\ndefault login d password z\n",
+			host:  "gitlab.example.com",
+			want:  "d",
+		},
+		{
+			name:  "host preferred over default",
+			netrc: "default login d password z\nmachine gitlab.example.com login a password x\n",
+			host:  "gitlab.example.com",
+			want:  "a",
+		},
+		{
+			name:  "no match",
+			netrc: "machine gitlab.com login a password x\n",
+			host:  "gitlab.example.com",
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := netrc.ParseString(tt.netrc)
+			if err != nil {
+				t.Fatalf("ParseString() error = %v", err)
+			}
+
+			got := ""
+			if machine := findHostMachine(n, tt.host); machine != nil {
+				got = machine.Get("login")
+			}
+			if got != tt.want {
+				t.Errorf("findHostMachine(%q) login = %q, want %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
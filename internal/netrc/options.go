@@ -11,6 +11,7 @@ import (
 type LoaderOptions struct {
 	Logger    logger.Logger
 	NetrcPath string
+	Host      string
 }
 
 // LoaderOption is a function that modifies LoaderOptions
@@ -30,6 +31,13 @@ func WithNetrcPath(path string) LoaderOption {
 	}
 }
 
+// WithHost sets the GitLab host whose credentials are looked up
+func WithHost(host string) LoaderOption {
+	return func(o *LoaderOptions) {
+		o.Host = host
+	}
+}
+
 // defaultLoaderOptions returns default loader options
 func defaultLoaderOptions() (*LoaderOptions, error) {
 	usr, err := user.Current()
@@ -40,5 +48,6 @@ func defaultLoaderOptions() (*LoaderOptions, error) {
 	return &LoaderOptions{
 		Logger:    nil,
 		NetrcPath: filepath.Join(usr.HomeDir, ".netrc"),
+		Host:      "",
 	}, nil
 }
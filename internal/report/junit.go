@@ -0,0 +1,110 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// skippedOutcomes are outcomes reported as skipped test cases
+var skippedOutcomes = map[string]bool{
+	"skipped":     true,
+	"dirty":       true,
+	"diverged":    true,
+	"no-upstream": true,
+	"conflict":    true,
+}
+
+// WriteJUnit writes the report as JUnit XML with one test case per project
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:      "glone " + r.Command,
+		Tests:     len(r.Projects),
+		Failures:  r.Failed(),
+		Time:      seconds(r.FinishedAt.Sub(r.StartedAt)),
+		Timestamp: r.StartedAt.Format(time.RFC3339),
+	}
+
+	for _, e := range r.Projects {
+		tc := junitTestCase{
+			Name:      e.Project,
+			ClassName: "glone." + r.Command,
+			Time:      seconds(e.Duration),
+			SystemOut: fmt.Sprintf("outcome: %s", e.Outcome),
+		}
+
+		if e.Commit != "" {
+			tc.SystemOut += fmt.Sprintf("\ncommit: %s", e.Commit)
+		}
+
+		switch {
+		case e.Outcome == OutcomeFailed:
+			tc.Failure = &junitMessage{Message: e.Error, Text: e.Error}
+		case skippedOutcomes[e.Outcome]:
+			tc.Skipped = &junitMessage{Message: e.Outcome}
+			suite.Skipped++
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	doc := junitTestSuites{
+		Name:     "glone",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
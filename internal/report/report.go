@@ -0,0 +1,129 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OutcomeFailed is the outcome of a project that could not be processed
+const OutcomeFailed = "failed"
+
+// Report formats
+const (
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+// Entry is the result of processing a single project
+type Entry struct {
+	Project  string        `json:"project"`
+	Outcome  string        `json:"outcome"`
+	Duration time.Duration `json:"-"`
+	Error    string        `json:"error,omitempty"`
+	Commit   string        `json:"commit,omitempty"`
+}
+
+// MarshalJSON encodes the duration in seconds
+func (e Entry) MarshalJSON() ([]byte, error) {
+	type entry Entry
+	return json.Marshal(struct {
+		entry
+		Duration float64 `json:"duration_seconds"`
+	}{
+		entry:    entry(e),
+		Duration: e.Duration.Seconds(),
+	})
+}
+
+// Report is a machine-readable summary of a run
+type Report struct {
+	Command    string         `json:"command"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	Counts     map[string]int `json:"counts"`
+	Projects   []Entry        `json:"projects"`
+}
+
+// New creates a report from the entries of a run, sorted by project path
+func New(command string, startedAt time.Time, entries []Entry) *Report {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Project < entries[j].Project
+	})
+
+	counts := make(map[string]int)
+	for _, e := range entries {
+		counts[e.Outcome]++
+	}
+
+	return &Report{
+		Command:    command,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+		Counts:     counts,
+		Projects:   entries,
+	}
+}
+
+// Failed returns the number of projects that failed
+func (r *Report) Failed() int {
+	return r.Counts[OutcomeFailed]
+}
+
+// FormatFromPath returns the report format for a file name:
+// junit for .xml files, json otherwise
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".xml") {
+		return FormatJUnit
+	}
+
+	return FormatJSON
+}
+
+// ValidateFormat returns an error if format is not a supported report format
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatJSON, FormatJUnit:
+		return nil
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// WriteFile writes the report to path in the given format. An existing file
+// is only replaced if the format is supported.
+func (r *Report) WriteFile(path string, format string) error {
+	if err := ValidateFormat(format); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer f.Close()
+
+	switch format {
+	case FormatJSON:
+		err = r.WriteJSON(f)
+	case FormatJUnit:
+		err = r.WriteJUnit(f)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return f.Close()
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,106 @@
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// headerRetryAfter is the standard header telling when to retry
+	headerRetryAfter = "Retry-After"
+	// headerRateLimitReset is the GitLab rate limit header holding the Unix time the limit resets
+	headerRateLimitReset = "RateLimit-Reset"
+)
+
+// Policy describes how failed operations are retried
+type Policy struct {
+	// Retries is the maximum number of retries after the first attempt
+	Retries int
+	// Backoff is the delay before the first retry, doubled for each further retry
+	Backoff time.Duration
+	// MaxBackoff caps the exponential delay; server-requested delays are not capped
+	MaxBackoff time.Duration
+}
+
+// DefaultPolicy returns the default retry policy
+func DefaultPolicy() Policy {
+	return Policy{
+		Retries:    3,
+		Backoff:    time.Second,
+		MaxBackoff: time.Minute,
+	}
+}
+
+// Delay returns how long to wait before retry number attempt (starting at 0).
+// If resp requests a delay with Retry-After or RateLimit-Reset, that delay is
+// used when longer than the exponential backoff.
+func (p Policy) Delay(attempt int, resp *http.Response) time.Duration {
+	delay := time.Duration(float64(p.Backoff) * math.Pow(2, float64(attempt)))
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	// Add up to 20% jitter so that parallel workers don't retry in lockstep
+	if delay > 0 {
+		delay += time.Duration(rand.Int64N(int64(delay)/5 + 1))
+	}
+
+	if wait := ServerDelay(resp); wait > delay {
+		return wait
+	}
+
+	return delay
+}
+
+// HTTPBackoff adapts the policy to the backoff callback of retryablehttp,
+// which the GitLab API client uses for its retries
+func (p Policy) HTTPBackoff(_, _ time.Duration, attempt int, resp *http.Response) time.Duration {
+	return p.Delay(attempt, resp)
+}
+
+// ServerDelay returns the delay requested by the server through the
+// Retry-After or GitLab's RateLimit-Reset header, or zero
+func ServerDelay(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+
+	if v := resp.Header.Get(headerRetryAfter); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+
+		if t, err := http.ParseTime(v); err == nil {
+			if wait := time.Until(t); wait > 0 {
+				return wait
+			}
+		}
+	}
+
+	if v := resp.Header.Get(headerRateLimitReset); v != "" {
+		if reset, err := strconv.ParseInt(v, 10, 64); err == nil && reset > 0 {
+			if wait := time.Until(time.Unix(reset, 0)); wait > 0 {
+				return wait
+			}
+		}
+	}
+
+	return 0
+}
+
+// Sleep waits for d or until ctx is cancelled
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func response(header string, value string) *http.Response {
+	resp := &http.Response{Header: make(http.Header)}
+	resp.Header.Set(header, value)
+
+	return resp
+}
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{Backoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		attempt int
+		resp    *http.Response
+		min     time.Duration
+		max     time.Duration
+	}{
+		{name: "first retry", policy: policy, attempt: 0, min: time.Second, max: 1200 * time.Millisecond},
+		{name: "doubled", policy: policy, attempt: 2, min: 4 * time.Second, max: 4800 * time.Millisecond},
+		{name: "capped", policy: policy, attempt: 10, min: 10 * time.Second, max: 12 * time.Second},
+		{name: "uncapped", policy: Policy{Backoff: time.Second}, attempt: 5, min: 32 * time.Second, max: 38400 * time.Millisecond},
+		{name: "no backoff", policy: Policy{}, attempt: 3, min: 0, max: 0},
+		{name: "longer server delay", policy: policy, attempt: 0, resp: response(headerRetryAfter, "30"), min: 30 * time.Second, max: 30 * time.Second},
+		{name: "shorter server delay", policy: policy, attempt: 2, resp: response(headerRetryAfter, "1"), min: 4 * time.Second, max: 4800 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				got := tt.policy.Delay(tt.attempt, tt.resp)
+				if got < tt.min || got > tt.max {
+					t.Fatalf("Delay(%d) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestServerDelay(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		resp *http.Response
+		min  time.Duration
+		max  time.Duration
+	}{
+		{name: "no response", resp: nil},
+		{name: "no headers", resp: &http.Response{Header: make(http.Header)}},
+		{name: "retry after seconds", resp: response(headerRetryAfter, "120"), min: 2 * time.Minute, max: 2 * time.Minute},
+		{name: "retry after zero", resp: response(headerRetryAfter, "0")},
+		{name: "retry after date", resp: response(headerRetryAfter, now.Add(time.Hour).UTC().Format(http.TimeFormat)), min: 58 * time.Minute, max: time.Hour},
+		{name: "retry after past date", resp: response(headerRetryAfter, now.Add(-time.Hour).UTC().Format(http.TimeFormat))},
+		{name: "retry after invalid", resp: response(headerRetryAfter, "soon")},
+		{name: "rate limit reset", resp: response(headerRateLimitReset, strconv.FormatInt(now.Add(time.Minute).Unix(), 10)), min: 58 * time.Second, max: time.Minute},
+		{name: "rate limit reset past", resp: response(headerRateLimitReset, strconv.FormatInt(now.Add(-time.Minute).Unix(), 10))},
+		{name: "rate limit reset invalid", resp: response(headerRateLimitReset, "later")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ServerDelay(tt.resp)
+			if got < tt.min || got > tt.max {
+				t.Errorf("ServerDelay() = %v, want between %v and %v", got, tt.min, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileName is the path of the state file relative to the target directory
+const FileName = ".glone/state.json"
+
+// Project is the recorded state of a single project
+type Project struct {
+	// ID is the GitLab project ID
+	ID int `json:"id"`
+	// Host is the GitLab host the project was cloned from
+	Host string `json:"host,omitempty"`
+	// Path is the path with namespace the project was cloned from
+	Path string `json:"path"`
+	// Protocol is the protocol the project was cloned with
+	Protocol string `json:"protocol,omitempty"`
+	// Branches maps branch names to the commit they pointed to after the last successful run
+	Branches map[string]string `json:"branches,omitempty"`
+	// LastActivityAt is the last activity of the project reported by GitLab
+	// at the last successful run
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+	// SyncedAt is the time the clone was last brought up to date
+	SyncedAt *time.Time `json:"synced_at,omitempty"`
+	// Options are the clone options that add content to a clone, such as LFS
+	// objects or submodules, it was last brought up to date with
+	Options []string `json:"options,omitempty"`
+	// Outcome is the outcome of the last run
+	Outcome string `json:"outcome,omitempty"`
+	// UpdatedAt is the time of the last run that processed the project
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// State holds the projects cloned in a target directory. Projects are
+// identified by their host and GitLab project ID, as IDs are only unique
+// within a GitLab instance. It is safe for concurrent use.
+type State struct {
+	mu       sync.Mutex
+	file     string
+	projects map[key]*Project
+}
+
+// key identifies a project in the state
+type key struct {
+	host string
+	id   int
+}
+
+// version is the version of the state file format
+const version = 1
+
+// file is the on-disk format of the state
+type file struct {
+	Version  int        `json:"version"`
+	Projects []*Project `json:"projects"`
+}
+
+// Load reads the state of targetDir. A missing state file yields an empty state.
+func Load(targetDir string) (*State, error) {
+	s := &State{
+		file:     filepath.Join(targetDir, FileName),
+		projects: make(map[key]*Project),
+	}
+
+	data, err := os.ReadFile(s.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", s.file, err)
+	}
+
+	if f.Version > version {
+		return nil, fmt.Errorf("state file %s has unsupported version %d", s.file, f.Version)
+	}
+
+	for _, p := range f.Projects {
+		s.projects[key{p.Host, p.ID}] = p
+	}
+
+	return s, nil
+}
+
+// Project returns a copy of the recorded state of a project of a host
+func (s *State) Project(host string, id int) (Project, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.projects[key{host, id}]
+	if !ok {
+		return Project{}, false
+	}
+
+	return *p, true
+}
+
+// Projects returns copies of the recorded projects of a host
+func (s *State) Projects(host string) []Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var projects []Project
+	for k, p := range s.projects {
+		if k.host == host {
+			projects = append(projects, *p)
+		}
+	}
+
+	return projects
+}
+
+// Hosts returns the sorted hosts projects are recorded for
+func (s *State) Hosts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var hosts []string
+	for k := range s.projects {
+		if !slices.Contains(hosts, k.host) {
+			hosts = append(hosts, k.host)
+		}
+	}
+	sort.Strings(hosts)
+
+	return hosts
+}
+
+// Update changes the recorded state of a project of a host, creating it if
+// necessary
+func (s *State) Update(host string, id int, fn func(p *Project)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.projects[key{host, id}]
+	if !ok {
+		p = &Project{ID: id, Host: host}
+		s.projects[key{host, id}] = p
+	}
+
+	fn(p)
+}
+
+// Save writes the state file, replacing it atomically
+func (s *State) Save() error {
+	s.mu.Lock()
+	f := file{
+		Version:  version,
+		Projects: make([]*Project, 0, len(s.projects)),
+	}
+	for _, p := range s.projects {
+		f.Projects = append(f.Projects, p)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(f.Projects, func(i, j int) bool {
+		if f.Projects[i].Host != f.Projects[j].Host {
+			return f.Projects[i].Host < f.Projects[j].Host
+		}
+		return f.Projects[i].ID < f.Projects[j].ID
+	})
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.file), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp := s.file + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.file); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	return nil
+}
@@ -3,10 +3,16 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	cli "github.com/urfave/cli/v3"
 
 	clone "github.com/adzpm/glone/internal/app/clone"
+	export "github.com/adzpm/glone/internal/app/export"
+	list "github.com/adzpm/glone/internal/app/list"
+	prune "github.com/adzpm/glone/internal/app/prune"
+	scrub "github.com/adzpm/glone/internal/app/scrub"
 	logger "github.com/adzpm/glone/internal/logger"
 )
 
@@ -28,6 +34,37 @@ func flags() []cli.Flag {
 			Usage:   "GitLab access token",
 			Sources: cli.EnvVars("GITLAB_TOKEN"),
 		},
+		&cli.StringFlag{
+			Name:    "token-command",
+			Usage:   "shell command printing the GitLab access token (e.g. \"pass show gitlab\")",
+			Sources: cli.EnvVars("GLONE_TOKEN_COMMAND"),
+		},
+		&cli.StringFlag{
+			Name:    "token-file",
+			Usage:   "file holding the GitLab access token, must not be readable by group or others",
+			Sources: cli.EnvVars("GLONE_TOKEN_FILE"),
+		},
+		&cli.StringFlag{
+			Name:    "credential-sources",
+			Usage:   "comma-separated order of credential sources: token-command, token-file, netrc, git-credential",
+			Value:   "token-command,token-file,netrc",
+			Sources: cli.EnvVars("GLONE_CREDENTIAL_SOURCES"),
+		},
+		&cli.StringFlag{
+			Name:    "netrc-file",
+			Usage:   "netrc file to load credentials from (default: ~/.netrc)",
+			Sources: cli.EnvVars("NETRC"),
+		},
+		&cli.StringFlag{
+			Name:    "config",
+			Usage:   "config file (default: ~/.config/glone/config.yaml)",
+			Sources: cli.EnvVars("GLONE_CONFIG"),
+		},
+		&cli.StringFlag{
+			Name:    "profile",
+			Usage:   "config file profile to use",
+			Sources: cli.EnvVars("GLONE_PROFILE"),
+		},
 	}
 }
 
@@ -47,10 +84,48 @@ func main() {
 				Flags:     clone.Flags(),
 				Action:    clone.Run,
 			},
+			{
+				Name:      "sync",
+				Usage:     "clones missing repositories and fast-forwards existing ones",
+				ArgsUsage: "[directory]",
+				Flags:     clone.Flags(),
+				Action:    clone.Sync,
+			},
+			{
+				Name:      "list",
+				Usage:     "lists the repositories clone would process and their local status",
+				ArgsUsage: "[directory]",
+				Flags:     list.Flags(),
+				Action:    list.Run,
+			},
+			{
+				Name:      "export",
+				Usage:     "exports issues, merge requests, discussions, labels and milestones to JSON files",
+				ArgsUsage: "[directory]",
+				Flags:     export.Flags(),
+				Action:    export.Run,
+			},
+			{
+				Name:      "prune",
+				Usage:     "removes or archives clones of projects that no longer exist on GitLab",
+				ArgsUsage: "[directory]",
+				Flags:     prune.Flags(),
+				Action:    prune.Run,
+			},
+			{
+				Name:      "scrub",
+				Usage:     "removes access tokens embedded in remote URLs by older versions",
+				ArgsUsage: "[directory]",
+				Action:    scrub.Run,
+			},
 		},
 	}
 
-	if err := app.Run(context.Background(), os.Args); err != nil {
+	// Cancel the context on Ctrl-C so that running commands can stop gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.Run(ctx, os.Args); err != nil {
 		lgr.Fatal(err)
 	}
 }